@@ -12,6 +12,7 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -21,6 +22,7 @@ import (
 	appsubv1 "github.com/open-cluster-management/multicloud-operators-subscription/pkg/apis"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/apis"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/controller"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/readiness"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/webhook"
 	"github.com/open-cluster-management/multicloudhub-operator/version"
 	netv1 "github.com/openshift/api/config/v1"
@@ -53,6 +55,9 @@ var (
 	metricsHost               = "0.0.0.0"
 	metricsPort         int32 = 8383
 	operatorMetricsPort int32 = 8686
+
+	// syncPeriod is how often watched resources are resynced so drift is corrected even without change events
+	syncPeriod = 10 * time.Minute
 )
 var log = logf.Log.WithName("cmd")
 
@@ -111,6 +116,7 @@ func main() {
 	options := manager.Options{
 		Namespace:          "",
 		MetricsBindAddress: fmt.Sprintf("%s:%d", metricsHost, metricsPort),
+		SyncPeriod:         &syncPeriod,
 	}
 
 	// Add support for MultiNamespace set in WATCH_NAMESPACE (e.g ns1,ns2)
@@ -183,6 +189,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Publish readiness to a file for file-based health checks
+	if path := os.Getenv(readiness.FileEnvVar); path != "" {
+		if err := mgr.Add(readiness.FileWriter(path)); err != nil {
+			log.Error(err, "")
+			os.Exit(1)
+		}
+	}
+
 	// Add the Metrics Service
 	addMetrics(ctx, cfg)
 
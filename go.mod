@@ -17,6 +17,7 @@ require (
 	github.com/openshift/api v3.9.1-0.20191111211345-a27ff30ebf09+incompatible
 	github.com/openshift/hive v1.0.18-0.20210129211840-21bce609f1f4
 	github.com/operator-framework/operator-sdk v0.18.0
+	github.com/prometheus/client_golang v1.7.1
 	github.com/spf13/pflag v1.0.5
 	go.uber.org/zap v1.15.0 // indirect
 	google.golang.org/protobuf v1.25.0 // indirect
@@ -5,6 +5,7 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -50,6 +51,10 @@ type MultiClusterHubSpec struct {
 	// +optional
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
 
+	// Tolerations added to hub component pods, alongside the default toleration of the infra node taint
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
 	// (Deprecated) Overrides for the default HiveConfig spec
 	// +optional
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
@@ -98,6 +103,165 @@ type Overrides struct {
 	// Pull policy of the MultiCluster hub images
 	// +optional
 	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// Type of the services created by the MultiClusterHub operator. Options are: ClusterIP (default) and NodePort
+	// +optional
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
+
+	// Node ports requested for NodePort services, keyed by service name. Ports not listed are assigned by the cluster
+	// +optional
+	NodePorts map[string]int32 `json:"nodePorts,omitempty"`
+
+	// Maximum time a first install may take to reach the Running phase before the hub is reported as Failed.
+	// Reconciliation continues after the timeout so the hub can still recover. Unset means no timeout
+	// +optional
+	InstallTimeout *metav1.Duration `json:"installTimeout,omitempty"`
+
+	// Time ranges in which component image updates may roll out. Other corrections are applied at any time.
+	// Updates are not restricted when no window is set
+	// +optional
+	UpdateWindow []TimeWindow `json:"updateWindow,omitempty"`
+
+	// Existing channel for subscriptions to use instead of a channel managed by the operator
+	// +optional
+	ExternalChannel *ChannelReference `json:"externalChannel,omitempty"`
+
+	// Query image registries to warn when a component image is not published for the architecture of a schedulable node
+	// +optional
+	VerifyImageArchitecture bool `json:"verifyImageArchitecture,omitempty"`
+
+	// Sources of environment variables for component containers, keyed by deployment name
+	// +optional
+	EnvFrom map[string][]corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// Pod Security admission level the hub namespace is labeled to enforce, warn and audit. Defaults to baseline
+	// +kubebuilder:validation:Enum=privileged;baseline;restricted
+	// +optional
+	PodSecurityLevel string `json:"podSecurityLevel,omitempty"`
+
+	// Suffix appended after a dash to the tag of component images, such as debug. Digest-pinned images are
+	// unchanged. Takes precedence over the mch-imageTagSuffix annotation
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_.-]{1,127}$`
+	// +optional
+	ImageTagSuffix string `json:"imageTagSuffix,omitempty"`
+
+	// Create Prometheus Operator ServiceMonitors scraping the hub component services that have a metrics port
+	// +optional
+	EnableServiceMonitors bool `json:"enableServiceMonitors,omitempty"`
+
+	// Create a dedicated ClusterIP Service exposing only the metrics port of each hub component that has one
+	// +optional
+	EnableMetricsServices bool `json:"enableMetricsServices,omitempty"`
+
+	// OLM ClusterServiceVersions that must reach the Succeeded phase before hub components are reconciled
+	// +optional
+	RequiredOperators []OperatorReference `json:"requiredOperators,omitempty"`
+
+	// Seccomp profile applied to component pods. Defaults to RuntimeDefault
+	// +optional
+	SeccompProfile *corev1.SeccompProfile `json:"seccompProfile,omitempty"`
+
+	// Warn when the running pods of a component deployment report different image digests for longer than a rollout takes
+	// +optional
+	VerifyImageDigests bool `json:"verifyImageDigests,omitempty"`
+
+	// Name of a secret in the hub namespace holding the ocm-webhook serving certificate (tls.crt, tls.key)
+	// and its CA (ca.crt). When set, the operator does not generate the webhook certificate
+	// +optional
+	WebhookTLSSecret string `json:"webhookTLSSecret,omitempty"`
+
+	// Scale the ocm-controller and ocm-proxyserver replicas with the number of managed clusters instead of the
+	// availability config
+	// +optional
+	ClusterScaling *ClusterScaling `json:"clusterScaling,omitempty"`
+
+	// URL the operator POSTs the hub name, namespace, version and config hash to when the hub first reaches
+	// the Running phase. Delivery is retried a bounded number of times and never blocks reconciliation
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +optional
+	ReadyCallbackURL string `json:"readyCallbackURL,omitempty"`
+
+	// Consecutive failed reconciles after which the operator stops reconciling the hub until its spec or the
+	// installer.open-cluster-management.io/resume annotation changes. Unset means reconciliation never backs off
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ReconcileErrorBudget int `json:"reconcileErrorBudget,omitempty"`
+
+	// Keep container resource requests and limits added to component deployments for resources the operator
+	// does not set. Values the operator sets still take precedence
+	// +optional
+	MergeContainerResources bool `json:"mergeContainerResources,omitempty"`
+
+	// Persistent volume claim the operator creates and mounts into the multiclusterhub-repo deployment for its
+	// chart cache. The deployment uses the Recreate strategy while the claim is mounted
+	// +optional
+	Persistence *PersistenceConfig `json:"persistence,omitempty"`
+}
+
+// ChannelReference identifies an application subscription channel
+type ChannelReference struct {
+	// Name of the channel
+	Name string `json:"name"`
+
+	// Namespace of the channel. Defaults to the MultiClusterHub namespace
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ClusterScaling sizes component replicas by the number of managed clusters
+type ClusterScaling struct {
+	// Managed clusters served by each replica. Defaults to 100
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ClustersPerReplica int32 `json:"clustersPerReplica,omitempty"`
+
+	// Fewest replicas to run. Defaults to the availability config replica count
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinReplicas int32 `json:"minReplicas,omitempty"`
+
+	// Most replicas to run
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+}
+
+// PersistenceConfig describes a persistent volume claim managed by the operator. The storage class and
+// access modes cannot change once the claim is created, and the claim can only be expanded
+type PersistenceConfig struct {
+	// Requested storage size. Defaults to 10Gi
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
+
+	// Storage class of the claim. Defaults to the cluster default storage class
+	// +optional
+	StorageClassName string `json:"storageClassName,omitempty"`
+
+	// Access modes of the claim. Defaults to ReadWriteOnce
+	// +optional
+	AccessModes []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+}
+
+// OperatorReference identifies an OLM ClusterServiceVersion the hub depends on
+type OperatorReference struct {
+	// Name of the ClusterServiceVersion
+	Name string `json:"name"`
+
+	// Namespace of the ClusterServiceVersion. Defaults to the MultiClusterHub namespace
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// TimeWindow is a recurring daily time range in UTC
+type TimeWindow struct {
+	// Days of the week the window opens on, e.g. Mon. Defaults to every day
+	// +optional
+	Days []string `json:"days,omitempty"`
+
+	// Start of the window in 24-hour HH:MM format
+	Start string `json:"start"`
+
+	// End of the window in 24-hour HH:MM format. An end before the start closes the window on the following day
+	End string `json:"end"`
 }
 
 type HiveConfigSpec struct {
@@ -215,6 +379,29 @@ type IngressSpec struct {
 	// List of SSL ciphers enabled for management ingress. Defaults to full list of supported ciphers
 	// +optional
 	SSLCiphers []string `json:"sslCiphers,omitempty"`
+
+	// Configuration for an OpenShift Route exposing the hub console
+	// +optional
+	Route *RouteSpec `json:"route,omitempty"`
+}
+
+// RouteSpec specifies configuration options for the hub console route
+type RouteSpec struct {
+	// Create a route to the hub console. Requires the route.openshift.io API
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Host of the route. Defaults to a host generated by the cluster
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// TLS termination of the route. Options are: passthrough (default), reencrypt and edge
+	// +optional
+	Termination string `json:"termination,omitempty"`
+
+	// Handling of insecure connections to the route. Options are: None (default), Allow and Redirect
+	// +optional
+	InsecureEdgeTerminationPolicy string `json:"insecureEdgeTerminationPolicy,omitempty"`
 }
 
 type HubPhaseType string
@@ -225,6 +412,7 @@ const (
 	HubInstalling   HubPhaseType = "Installing"
 	HubUpdating     HubPhaseType = "Updating"
 	HubUninstalling HubPhaseType = "Uninstalling"
+	HubFailed       HubPhaseType = "Failed"
 )
 
 // MultiClusterHubStatus defines the observed state of MultiClusterHub
@@ -242,6 +430,14 @@ type MultiClusterHubStatus struct {
 	// +optional
 	DesiredVersion string `json:"desiredVersion,omitempty"`
 
+	// InstallStartTime is when the operator began installing the MultiClusterHub
+	// +optional
+	InstallStartTime *metav1.Time `json:"installStartTime,omitempty"`
+
+	// NextUpdateWindow is when deferred component image updates are next allowed to roll out
+	// +optional
+	NextUpdateWindow *metav1.Time `json:"nextUpdateWindow,omitempty"`
+
 	// Conditions contains the different condition statuses for the MultiClusterHub
 	// +optional
 	HubConditions []HubCondition `json:"conditions,omitempty"`
@@ -293,6 +489,30 @@ const (
 
 	// Terminating means that the multiclusterhub has been deleted and is cleaning up.
 	Terminating HubConditionType = "Terminating"
+
+	// ConfigError means the multiclusterhub spec cannot be applied as configured.
+	ConfigError HubConditionType = "ConfigError"
+
+	// QuotaExceeded means the resources requested by hub components exceed a resource quota in the namespace.
+	QuotaExceeded HubConditionType = "QuotaExceeded"
+
+	// PendingUpdate means component image updates are deferred until the next update window.
+	PendingUpdate HubConditionType = "PendingUpdate"
+
+	// ArchitectureMismatch means a component image is not published for the architecture of a schedulable node.
+	ArchitectureMismatch HubConditionType = "ArchitectureMismatch"
+
+	// OwnershipConflict means a managed resource is claimed by another controller and is no longer updated.
+	OwnershipConflict HubConditionType = "OwnershipConflict"
+
+	// ScaleDownBlocked means a component is kept at its current replicas because a PodDisruptionBudget requires them.
+	ScaleDownBlocked HubConditionType = "ScaleDownBlocked"
+
+	// DigestMismatch means the running pods of a component have reported different image digests past the grace period.
+	DigestMismatch HubConditionType = "DigestMismatch"
+
+	// PrerequisitesMissing means components are not installed because API groups they require are not served.
+	PrerequisitesMissing HubConditionType = "PrerequisitesMissing"
 )
 
 // StatusCondition contains condition information.
@@ -8,6 +8,7 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -33,6 +34,38 @@ func (in *BackupConfig) DeepCopy() *BackupConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelReference) DeepCopyInto(out *ChannelReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelReference.
+func (in *ChannelReference) DeepCopy() *ChannelReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterScaling) DeepCopyInto(out *ClusterScaling) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterScaling.
+func (in *ClusterScaling) DeepCopy() *ClusterScaling {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterScaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalDNSAWSConfig) DeepCopyInto(out *ExternalDNSAWSConfig) {
 	*out = *in
@@ -189,6 +222,11 @@ func (in *IngressSpec) DeepCopyInto(out *IngressSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Route != nil {
+		in, out := &in.Route, &out.Route
+		*out = new(RouteSpec)
+		**out = **in
+	}
 	return
 }
 
@@ -273,6 +311,13 @@ func (in *MultiClusterHubSpec) DeepCopyInto(out *MultiClusterHubSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Hive != nil {
 		in, out := &in.Hive, &out.Hive
 		*out = new(HiveConfigSpec)
@@ -282,7 +327,7 @@ func (in *MultiClusterHubSpec) DeepCopyInto(out *MultiClusterHubSpec) {
 	if in.Overrides != nil {
 		in, out := &in.Overrides, &out.Overrides
 		*out = new(Overrides)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -300,6 +345,14 @@ func (in *MultiClusterHubSpec) DeepCopy() *MultiClusterHubSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MultiClusterHubStatus) DeepCopyInto(out *MultiClusterHubStatus) {
 	*out = *in
+	if in.InstallStartTime != nil {
+		in, out := &in.InstallStartTime, &out.InstallStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextUpdateWindow != nil {
+		in, out := &in.NextUpdateWindow, &out.NextUpdateWindow
+		*out = (*in).DeepCopy()
+	}
 	if in.HubConditions != nil {
 		in, out := &in.HubConditions, &out.HubConditions
 		*out = make([]HubCondition, len(*in))
@@ -327,9 +380,86 @@ func (in *MultiClusterHubStatus) DeepCopy() *MultiClusterHubStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorReference) DeepCopyInto(out *OperatorReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorReference.
+func (in *OperatorReference) DeepCopy() *OperatorReference {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Overrides) DeepCopyInto(out *Overrides) {
 	*out = *in
+	if in.NodePorts != nil {
+		in, out := &in.NodePorts, &out.NodePorts
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.InstallTimeout != nil {
+		in, out := &in.InstallTimeout, &out.InstallTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.UpdateWindow != nil {
+		in, out := &in.UpdateWindow, &out.UpdateWindow
+		*out = make([]TimeWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExternalChannel != nil {
+		in, out := &in.ExternalChannel, &out.ExternalChannel
+		*out = new(ChannelReference)
+		**out = **in
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make(map[string][]corev1.EnvFromSource, len(*in))
+		for key, val := range *in {
+			var outVal []corev1.EnvFromSource
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]corev1.EnvFromSource, len(*in))
+				for i := range *in {
+					(*in)[i].DeepCopyInto(&(*out)[i])
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.RequiredOperators != nil {
+		in, out := &in.RequiredOperators, &out.RequiredOperators
+		*out = make([]OperatorReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.SeccompProfile != nil {
+		in, out := &in.SeccompProfile, &out.SeccompProfile
+		*out = new(corev1.SeccompProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterScaling != nil {
+		in, out := &in.ClusterScaling, &out.ClusterScaling
+		*out = new(ClusterScaling)
+		**out = **in
+	}
+	if in.Persistence != nil {
+		in, out := &in.Persistence, &out.Persistence
+		*out = new(PersistenceConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -343,6 +473,48 @@ func (in *Overrides) DeepCopy() *Overrides {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistenceConfig) DeepCopyInto(out *PersistenceConfig) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.AccessModes != nil {
+		in, out := &in.AccessModes, &out.AccessModes
+		*out = make([]corev1.PersistentVolumeAccessMode, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistenceConfig.
+func (in *PersistenceConfig) DeepCopy() *PersistenceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistenceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteSpec) DeepCopyInto(out *RouteSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteSpec.
+func (in *RouteSpec) DeepCopy() *RouteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StatusCondition) DeepCopyInto(out *StatusCondition) {
 	*out = *in
@@ -361,6 +533,27 @@ func (in *StatusCondition) DeepCopy() *StatusCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeWindow) DeepCopyInto(out *TimeWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeWindow.
+func (in *TimeWindow) DeepCopy() *TimeWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VeleroBackupConfig) DeepCopyInto(out *VeleroBackupConfig) {
 	*out = *in
@@ -26,6 +26,29 @@ func channelURL(m *operatorsv1.MultiClusterHub) string {
 	return fmt.Sprintf("http://%s.%s.svc.cluster.local:%d/charts", helmrepo.HelmRepoName, m.Namespace, helmrepo.Port)
 }
 
+// IsExternal returns true if subscriptions use an existing channel not managed by the operator
+func IsExternal(m *operatorsv1.MultiClusterHub) bool {
+	return m.Spec.Overrides != nil && m.Spec.Overrides.ExternalChannel != nil && m.Spec.Overrides.ExternalChannel.Name != ""
+}
+
+// Key returns the name and namespace of the channel used by subscriptions
+func Key(m *operatorsv1.MultiClusterHub) (name string, namespace string) {
+	if !IsExternal(m) {
+		return ChannelName, m.Namespace
+	}
+	ec := m.Spec.Overrides.ExternalChannel
+	if ec.Namespace == "" {
+		return ec.Name, m.Namespace
+	}
+	return ec.Name, ec.Namespace
+}
+
+// Reference returns the namespace/name reference to the channel used by subscriptions
+func Reference(m *operatorsv1.MultiClusterHub) string {
+	name, namespace := Key(m)
+	return namespace + "/" + name
+}
+
 // Channel returns an unstructured Channel object to watch the helm repository
 func Channel(m *operatorsv1.MultiClusterHub) *unstructured.Unstructured {
 	ch := &unstructured.Unstructured{
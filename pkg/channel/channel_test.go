@@ -0,0 +1,52 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package channel
+
+import (
+	"testing"
+
+	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReference(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides *operatorsv1.Overrides
+		external  bool
+		want      string
+	}{
+		{
+			name:     "Managed channel",
+			external: false,
+			want:     "testNS/" + ChannelName,
+		},
+		{
+			name:      "External channel in hub namespace",
+			overrides: &operatorsv1.Overrides{ExternalChannel: &operatorsv1.ChannelReference{Name: "gitops-charts"}},
+			external:  true,
+			want:      "testNS/gitops-charts",
+		},
+		{
+			name:      "External channel in another namespace",
+			overrides: &operatorsv1.Overrides{ExternalChannel: &operatorsv1.ChannelReference{Name: "gitops-charts", Namespace: "gitops"}},
+			external:  true,
+			want:      "gitops/gitops-charts",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &operatorsv1.MultiClusterHub{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testNS"},
+				Spec:       operatorsv1.MultiClusterHubSpec{Overrides: tt.overrides},
+			}
+			if got := IsExternal(m); got != tt.external {
+				t.Errorf("IsExternal() = %v, want %v", got, tt.external)
+			}
+			if got := Reference(m); got != tt.want {
+				t.Errorf("Reference() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
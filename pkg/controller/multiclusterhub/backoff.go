@@ -0,0 +1,68 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package multiclusterhub
+
+import (
+	"fmt"
+
+	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// reconcileBackoff records the hub generation and resume request seen when the error budget was exhausted
+type reconcileBackoff struct {
+	generation int64
+	resume     string
+}
+
+// recordReconcileResult counts consecutive reconcile errors and backs off reconciling the hub once
+// its error budget is exhausted
+func (r *ReconcileMultiClusterHub) recordReconcileResult(m *operatorsv1.MultiClusterHub, err error) {
+	state := r.state(m)
+	if state.backoff != nil {
+		return
+	}
+	if err == nil {
+		state.reconcileErrors = 0
+		return
+	}
+
+	state.reconcileErrors++
+	budget := utils.GetReconcileErrorBudget(m)
+	if budget == 0 || state.reconcileErrors < budget {
+		return
+	}
+
+	state.backoff = &reconcileBackoff{generation: m.GetGeneration(), resume: utils.GetResumeRequest(m)}
+	message := fmt.Sprintf("Reconcile failed %d consecutive times, last with: %s. Reconciliation is backed off until the spec or the %s annotation changes",
+		state.reconcileErrors, err.Error(), utils.AnnotationResume)
+	log.Info("Backing off reconciliation", "Errors", state.reconcileErrors, "Error", err.Error())
+	r.recorder.Event(m, corev1.EventTypeWarning, ReconcileBackedOffReason, message)
+	condition := NewHubCondition(operatorsv1.Progressing, metav1.ConditionFalse, ReconcileBackedOffReason, message)
+	SetHubCondition(&m.Status, *condition)
+}
+
+// backedOff returns true while reconciliation of the hub is backed off. Reconciliation resumes once
+// the spec generation or the resume annotation changes, or the error budget is removed
+func (r *ReconcileMultiClusterHub) backedOff(m *operatorsv1.MultiClusterHub) bool {
+	state := r.state(m)
+	if state.backoff == nil {
+		return false
+	}
+	if m.GetGeneration() == state.backoff.generation && utils.GetResumeRequest(m) == state.backoff.resume &&
+		utils.GetReconcileErrorBudget(m) > 0 {
+		return true
+	}
+
+	log.Info("Resuming reconciliation after backing off")
+	state.backoff = nil
+	state.reconcileErrors = 0
+	if c := GetHubCondition(m.Status, operatorsv1.Progressing); c != nil && c.Reason == ReconcileBackedOffReason {
+		condition := NewHubCondition(operatorsv1.Progressing, metav1.ConditionTrue, ResumedReason, "Multiclusterhub is resumed")
+		SetHubCondition(&m.Status, *condition)
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package multiclusterhub
+
+import (
+	e "errors"
+	"testing"
+
+	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func Test_reconcileBackoff(t *testing.T) {
+	mch := full_mch.DeepCopy()
+	mch.Spec.Overrides = &operatorsv1.Overrides{ReconcileErrorBudget: 3}
+	mch.SetGeneration(1)
+	r, err := getTestReconciler(mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+	reconcileErr := e.New("bad configuration")
+
+	// A successful reconcile resets the count
+	r.recordReconcileResult(mch, reconcileErr)
+	r.recordReconcileResult(mch, nil)
+	r.recordReconcileResult(mch, reconcileErr)
+	r.recordReconcileResult(mch, reconcileErr)
+	if r.backedOff(mch) {
+		t.Fatalf("Backed off before the error budget was exhausted")
+	}
+
+	r.recordReconcileResult(mch, reconcileErr)
+	if !r.backedOff(mch) {
+		t.Fatalf("Did not back off once the error budget was exhausted")
+	}
+	if c := GetHubCondition(mch.Status, operatorsv1.Progressing); c == nil || c.Status != metav1.ConditionFalse || c.Reason != ReconcileBackedOffReason {
+		t.Errorf("Progressing condition = %v, want %s", c, ReconcileBackedOffReason)
+	}
+	if phase := calculateStatus(mch, nil, nil, nil, nil, nil).Phase; phase != operatorsv1.HubFailed {
+		t.Errorf("Phase = %s, want %s", phase, operatorsv1.HubFailed)
+	}
+
+	t.Run("Other hubs", func(t *testing.T) {
+		other := mch.DeepCopy()
+		other.SetName("other")
+		other.SetGeneration(5)
+		other.Status = operatorsv1.MultiClusterHubStatus{}
+		if r.backedOff(other) {
+			t.Fatalf("Another hub is backed off")
+		}
+		for i := 0; i < 2; i++ {
+			r.recordReconcileResult(other, reconcileErr)
+		}
+		if r.backedOff(other) {
+			t.Fatalf("Another hub backed off before its own error budget was exhausted")
+		}
+		if !r.backedOff(mch) {
+			t.Fatalf("Another hub resumed reconciliation of the backed off hub")
+		}
+	})
+
+	t.Run("Deleted hub", func(t *testing.T) {
+		r.state(&operatorsv1.MultiClusterHub{ObjectMeta: metav1.ObjectMeta{Name: "deleted", Namespace: mch.Namespace}})
+		key := types.NamespacedName{Name: "deleted", Namespace: mch.Namespace}
+		if _, err := r.Reconcile(reconcile.Request{NamespacedName: key}); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		if _, ok := r.hubs[key]; ok {
+			t.Errorf("Reconcile() kept the state of a deleted hub")
+		}
+	})
+
+	t.Run("Resume request", func(t *testing.T) {
+		mch.SetAnnotations(map[string]string{utils.AnnotationResume: "1"})
+		if r.backedOff(mch) {
+			t.Fatalf("Still backed off after a resume request")
+		}
+		if c := GetHubCondition(mch.Status, operatorsv1.Progressing); c == nil || c.Reason != ResumedReason {
+			t.Errorf("Progressing condition = %v, want %s", c, ResumedReason)
+		}
+	})
+
+	t.Run("Spec change", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			r.recordReconcileResult(mch, reconcileErr)
+		}
+		if !r.backedOff(mch) {
+			t.Fatalf("Did not back off once the error budget was exhausted")
+		}
+		mch.SetGeneration(2)
+		if r.backedOff(mch) {
+			t.Fatalf("Still backed off after a spec change")
+		}
+	})
+
+	t.Run("No budget", func(t *testing.T) {
+		mch.Spec.Overrides = nil
+		for i := 0; i < 10; i++ {
+			r.recordReconcileResult(mch, reconcileErr)
+		}
+		if r.backedOff(mch) {
+			t.Errorf("Backed off without an error budget")
+		}
+	})
+}
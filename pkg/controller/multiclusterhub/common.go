@@ -5,10 +5,12 @@ package multiclusterhub
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	e "errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
@@ -17,22 +19,36 @@ import (
 
 	subrelv1 "github.com/open-cluster-management/multicloud-operators-subscription-release/pkg/apis/apps/v1"
 	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/channel"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/events"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/foundation"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/helmrepo"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/manifest"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/metrics"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/registry"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/route"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/servicemonitor"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/subscription"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/supportbundle"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/utils"
 	"github.com/open-cluster-management/multicloudhub-operator/version"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/discovery"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
@@ -48,14 +64,41 @@ type CacheSpec struct {
 	ImageSuffix       string
 	ManifestVersion   string
 	ImageOverridesCM  string
+	// ClusterScaledReplicas sizes the components serving managed clusters, or is 0 to use the availability config
+	ClusterScaledReplicas int32
+	// Prerequisites lists the API group versions each component subscription requires before it is installed
+	Prerequisites map[string][]schema.GroupVersion
 }
 
 func (r *ReconcileMultiClusterHub) ensureDeployment(m *operatorsv1.MultiClusterHub, dep *appsv1.Deployment) (*reconcile.Result, error) {
 	dplog := log.WithValues("Deployment.Namespace", dep.Namespace, "Deployment.Name", dep.Name)
 
+	// Size the components serving managed clusters by the fleet rather than the availability config
+	clusterScaled := r.CacheSpec.ClusterScaledReplicas > 0 &&
+		(dep.Name == foundation.OCMControllerName || dep.Name == foundation.OCMProxyServerName)
+	if clusterScaled {
+		replicas := r.CacheSpec.ClusterScaledReplicas
+		dep.Spec.Replicas = &replicas
+	}
+
+	if result, err := r.ensureEnvFromSources(m, dep); result != nil {
+		return result, err
+	}
+
+	// Stamp the pod template with the content of its configmaps and secrets so pods roll when they change
+	configHash, err := utils.ConfigHash(r.client, m.Namespace, &dep.Spec.Template)
+	if err != nil {
+		dplog.Error(err, "Failed to compute configuration hash")
+		return &reconcile.Result{}, err
+	}
+	if dep.Spec.Template.Annotations == nil {
+		dep.Spec.Template.Annotations = map[string]string{}
+	}
+	dep.Spec.Template.Annotations[utils.AnnotationConfigHash] = configHash
+
 	// See if deployment already exists and create if it doesn't
 	found := &appsv1.Deployment{}
-	err := r.client.Get(context.TODO(), types.NamespacedName{
+	err = r.client.Get(context.TODO(), types.NamespacedName{
 		Name:      dep.Name,
 		Namespace: m.Namespace,
 	}, found)
@@ -66,11 +109,13 @@ func (r *ReconcileMultiClusterHub) ensureDeployment(m *operatorsv1.MultiClusterH
 		if err != nil {
 			// Deployment failed
 			dplog.Error(err, "Failed to create new Deployment")
+			r.recorder.Eventf(m, corev1.EventTypeWarning, events.CreateFailedReason, "Failed to create Deployment %s: %s", dep.Name, err.Error())
 			return &reconcile.Result{}, err
 		}
 
 		// Deployment was successful
 		dplog.Info("Created a new Deployment")
+		r.recorder.Eventf(m, corev1.EventTypeNormal, events.CreatedReason, "Created Deployment %s", dep.Name)
 		condition := NewHubCondition(operatorsv1.Progressing, metav1.ConditionTrue, NewComponentReason, "Created new resource")
 		SetHubCondition(&m.Status, *condition)
 		return nil, nil
@@ -81,6 +126,10 @@ func (r *ReconcileMultiClusterHub) ensureDeployment(m *operatorsv1.MultiClusterH
 		return &reconcile.Result{}, err
 	}
 
+	if r.checkOwnership(m, "Deployment", found) {
+		return nil, nil
+	}
+
 	// Validate object based on name
 	var desired *appsv1.Deployment
 	var needsUpdate bool
@@ -95,12 +144,72 @@ func (r *ReconcileMultiClusterHub) ensureDeployment(m *operatorsv1.MultiClusterH
 		return nil, nil
 	}
 
+	if clusterScaled && *desired.Spec.Replicas != *dep.Spec.Replicas {
+		desired.Spec.Replicas = dep.Spec.Replicas
+		needsUpdate = deploymentChanged(found, desired)
+	}
+
+	// Keep the current replicas when scaling down would leave fewer pods than a disruption budget requires
+	pdb, err := r.blockingDisruptionBudget(found, desired)
+	if err != nil {
+		dplog.Error(err, "Failed to check PodDisruptionBudgets")
+		return &reconcile.Result{}, err
+	}
+	state := r.state(m)
+	if pdb != "" {
+		blocked := fmt.Sprintf("%d replicas by PodDisruptionBudget %s", *desired.Spec.Replicas, pdb)
+		if state.blockedScaleDowns == nil {
+			state.blockedScaleDowns = map[string]string{}
+		}
+		if _, ok := state.blockedScaleDowns[dep.Name]; !ok {
+			dplog.Info("Not scaling down deployment below its disruption budget", "Replicas", *desired.Spec.Replicas, "PodDisruptionBudget", pdb)
+			r.recorder.Eventf(m, corev1.EventTypeWarning, DisruptionBudgetReason,
+				"Deployment %s is not scaled down to %d replicas because PodDisruptionBudget %s requires more available pods",
+				dep.Name, *desired.Spec.Replicas, pdb)
+		}
+		state.blockedScaleDowns[dep.Name] = blocked
+		desired.Spec.Replicas = found.Spec.Replicas
+		needsUpdate = deploymentChanged(found, desired)
+	} else {
+		delete(state.blockedScaleDowns, dep.Name)
+	}
+	r.updateScaleDownCondition(m)
+
+	// Defer image changes outside of the update window while still applying other corrections
+	if needsUpdate && !utils.InUpdateWindow(m, time.Now()) && deferImageUpdates(found, desired) {
+		dplog.Info("Deferring image update until the next update window")
+		needsUpdate = deploymentChanged(found, desired)
+		next := metav1.NewTime(utils.NextUpdateWindow(m, time.Now()))
+		m.Status.NextUpdateWindow = &next
+		condition := NewHubCondition(operatorsv1.PendingUpdate, metav1.ConditionTrue, UpdateDeferredReason,
+			fmt.Sprintf("Component image updates are deferred until the next update window at %s", next.UTC().Format(time.RFC3339)))
+		SetHubCondition(&m.Status, *condition)
+		if state.deferredUpdates == nil {
+			state.deferredUpdates = map[string]bool{}
+		}
+		state.deferredUpdates[dep.Name] = true
+	} else {
+		delete(state.deferredUpdates, dep.Name)
+	}
+
+	if desired.Spec.Template.Annotations[utils.AnnotationConfigHash] != configHash {
+		dplog.Info("Configuration changed; restarting pods")
+		if desired.Spec.Template.Annotations == nil {
+			desired.Spec.Template.Annotations = map[string]string{}
+		}
+		desired.Spec.Template.Annotations[utils.AnnotationConfigHash] = configHash
+		needsUpdate = true
+	}
+
 	if needsUpdate {
 		err = r.client.Update(context.TODO(), desired)
 		if err != nil {
 			dplog.Error(err, "Failed to update Deployment.")
+			r.recorder.Eventf(m, corev1.EventTypeWarning, events.UpdateFailedReason, "Failed to update Deployment %s: %s", dep.Name, err.Error())
 			return &reconcile.Result{}, err
 		}
+		r.recorder.Eventf(m, corev1.EventTypeNormal, events.UpdatedReason, "Updated Deployment %s", dep.Name)
+		metrics.RecordDriftCorrection("Deployment", dep.Name)
 		// Spec updated - return
 		return nil, nil
 	}
@@ -123,11 +232,13 @@ func (r *ReconcileMultiClusterHub) ensureService(m *operatorsv1.MultiClusterHub,
 		if err != nil {
 			// Creation failed
 			svlog.Error(err, "Failed to create new Service")
+			r.recorder.Eventf(m, corev1.EventTypeWarning, events.CreateFailedReason, "Failed to create Service %s: %s", s.Name, err.Error())
 			return &reconcile.Result{}, err
 		}
 
 		// Creation was successful
 		svlog.Info("Created a new Service")
+		r.recorder.Eventf(m, corev1.EventTypeNormal, events.CreatedReason, "Created Service %s", s.Name)
 		condition := NewHubCondition(operatorsv1.Progressing, metav1.ConditionTrue, NewComponentReason, "Created new resource")
 		SetHubCondition(&m.Status, *condition)
 		return nil, nil
@@ -138,213 +249,944 @@ func (r *ReconcileMultiClusterHub) ensureService(m *operatorsv1.MultiClusterHub,
 		return &reconcile.Result{}, err
 	}
 
+	if r.checkOwnership(m, "Service", found) {
+		return nil, nil
+	}
+
+	updated, needsUpdate := utils.ValidateService(found, s)
+	if needsUpdate {
+		svlog.Info("Updating Service")
+		err = r.client.Update(context.TODO(), updated)
+		if err != nil {
+			svlog.Error(err, "Failed to update Service")
+			r.recorder.Eventf(m, corev1.EventTypeWarning, events.UpdateFailedReason, "Failed to update Service %s: %s", s.Name, err.Error())
+			return &reconcile.Result{}, err
+		}
+		r.recorder.Eventf(m, corev1.EventTypeNormal, events.UpdatedReason, "Updated Service %s", s.Name)
+		metrics.RecordDriftCorrection("Service", s.Name)
+	}
+
 	return nil, nil
 }
 
-func (r *ReconcileMultiClusterHub) ensureAPIService(m *operatorsv1.MultiClusterHub, s *apiregistrationv1.APIService) (*reconcile.Result, error) {
-	svlog := log.WithValues("Service.Name", s.Name)
+// ensurePVC creates the persistent volume claim or expands it to the desired size, requeueing while the claim
+// is pending
+func (r *ReconcileMultiClusterHub) ensurePVC(m *operatorsv1.MultiClusterHub, pvc *corev1.PersistentVolumeClaim) (*reconcile.Result, error) {
+	pvclog := log.WithValues("PersistentVolumeClaim.Namespace", pvc.Namespace, "PersistentVolumeClaim.Name", pvc.Name)
 
-	found := &apiregistrationv1.APIService{}
+	found := &corev1.PersistentVolumeClaim{}
 	err := r.client.Get(context.TODO(), types.NamespacedName{
-		Name: s.Name,
+		Name:      pvc.Name,
+		Namespace: m.Namespace,
 	}, found)
 	if err != nil && errors.IsNotFound(err) {
 
-		// Create the apiService
-		err = r.client.Create(context.TODO(), s)
-
+		// Create the claim
+		err = r.client.Create(context.TODO(), pvc)
 		if err != nil {
 			// Creation failed
-			svlog.Error(err, "Failed to create new apiService")
+			pvclog.Error(err, "Failed to create new PersistentVolumeClaim")
+			r.recorder.Eventf(m, corev1.EventTypeWarning, events.CreateFailedReason, "Failed to create PersistentVolumeClaim %s: %s", pvc.Name, err.Error())
 			return &reconcile.Result{}, err
 		}
 
 		// Creation was successful
-		svlog.Info("Created a new apiService")
+		pvclog.Info("Created a new PersistentVolumeClaim")
+		r.recorder.Eventf(m, corev1.EventTypeNormal, events.CreatedReason, "Created PersistentVolumeClaim %s", pvc.Name)
 		condition := NewHubCondition(operatorsv1.Progressing, metav1.ConditionTrue, NewComponentReason, "Created new resource")
 		SetHubCondition(&m.Status, *condition)
+		return &reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+
+	} else if err != nil {
+		// Error that isn't due to the claim not existing
+		pvclog.Error(err, "Failed to get PersistentVolumeClaim")
+		return &reconcile.Result{}, err
+	}
+
+	if r.checkOwnership(m, "PersistentVolumeClaim", found) {
+		return nil, nil
+	}
+
+	updated, needsUpdate := utils.ValidatePersistentVolumeClaim(found, pvc)
+	if needsUpdate {
+		pvclog.Info("Expanding PersistentVolumeClaim")
+		err = r.client.Update(context.TODO(), updated)
+		if err != nil {
+			pvclog.Error(err, "Failed to update PersistentVolumeClaim")
+			r.recorder.Eventf(m, corev1.EventTypeWarning, events.UpdateFailedReason, "Failed to update PersistentVolumeClaim %s: %s", pvc.Name, err.Error())
+			return &reconcile.Result{}, err
+		}
+		r.recorder.Eventf(m, corev1.EventTypeNormal, events.UpdatedReason, "Updated PersistentVolumeClaim %s", pvc.Name)
+		metrics.RecordDriftCorrection("PersistentVolumeClaim", pvc.Name)
+	}
+
+	if found.Status.Phase == corev1.ClaimPending {
+		pvclog.Info("Waiting for PersistentVolumeClaim to be bound")
+		return &reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	return nil, nil
+}
+
+// ensureEnvFromSources requeues until the configmaps and secrets the deployment reads environment variables
+// from exist. Optional sources are not required.
+func (r *ReconcileMultiClusterHub) ensureEnvFromSources(m *operatorsv1.MultiClusterHub, dep *appsv1.Deployment) (*reconcile.Result, error) {
+	var missing []string
+	for _, c := range dep.Spec.Template.Spec.Containers {
+		for _, ef := range c.EnvFrom {
+			var obj runtime.Object
+			var name, kind string
+			switch {
+			case ef.ConfigMapRef != nil && (ef.ConfigMapRef.Optional == nil || !*ef.ConfigMapRef.Optional):
+				obj, name, kind = &corev1.ConfigMap{}, ef.ConfigMapRef.Name, "configmap"
+			case ef.SecretRef != nil && (ef.SecretRef.Optional == nil || !*ef.SecretRef.Optional):
+				obj, name, kind = &corev1.Secret{}, ef.SecretRef.Name, "secret"
+			default:
+				continue
+			}
+			err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: dep.Namespace}, obj)
+			if errors.IsNotFound(err) {
+				missing = append(missing, fmt.Sprintf("%s %s", kind, name))
+			} else if err != nil {
+				log.Error(err, "Failed to get environment variable source", "Name", name)
+				return &reconcile.Result{}, err
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		message := fmt.Sprintf("Deployment %s is waiting for environment variable sources: %s", dep.Name, strings.Join(missing, ", "))
+		log.Info(message)
+		condition := NewHubCondition(operatorsv1.ConfigError, metav1.ConditionTrue, EnvFromSourceMissingReason, message)
+		SetHubCondition(&m.Status, *condition)
+		return &reconcile.Result{RequeueAfter: resyncPeriod}, nil
+	}
+
+	removeConfigError(m, EnvFromSourceMissingReason)
+	return nil, nil
+}
+
+// blockingDisruptionBudget returns the name of a PodDisruptionBudget selecting the deployment's pods whose
+// minAvailable exceeds the desired replicas, if the desired spec scales the deployment down
+func (r *ReconcileMultiClusterHub) blockingDisruptionBudget(found, desired *appsv1.Deployment) (string, error) {
+	if found.Spec.Replicas == nil || desired.Spec.Replicas == nil || *desired.Spec.Replicas >= *found.Spec.Replicas {
+		return "", nil
+	}
+
+	pdbList := &policyv1beta1.PodDisruptionBudgetList{}
+	err := r.client.List(context.TODO(), pdbList, client.InNamespace(found.Namespace))
+	if err != nil {
+		return "", err
+	}
+
+	podLabels := labels.Set(found.Spec.Template.Labels)
+	for _, pdb := range pdbList.Items {
+		if pdb.Spec.MinAvailable == nil || pdb.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() || !selector.Matches(podLabels) {
+			continue
+		}
+		minAvailable, err := intstr.GetValueFromIntOrPercent(pdb.Spec.MinAvailable, int(*found.Spec.Replicas), true)
+		if err != nil {
+			continue
+		}
+		if int32(minAvailable) > *desired.Spec.Replicas {
+			return pdb.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// updateScaleDownCondition sets the ScaleDownBlocked condition to name every deployment held at its current
+// replicas, removing it once none remain
+func (r *ReconcileMultiClusterHub) updateScaleDownCondition(m *operatorsv1.MultiClusterHub) {
+	state := r.state(m)
+	if len(state.blockedScaleDowns) == 0 {
+		RemoveHubCondition(&m.Status, operatorsv1.ScaleDownBlocked)
+		return
+	}
+
+	blocked := []string{}
+	for name, description := range state.blockedScaleDowns {
+		blocked = append(blocked, fmt.Sprintf("%s to %s", name, description))
+	}
+	sort.Strings(blocked)
+	message := fmt.Sprintf("Deployments are not scaled down because PodDisruptionBudgets require more available pods: %s", strings.Join(blocked, "; "))
+
+	condition := NewHubCondition(operatorsv1.ScaleDownBlocked, metav1.ConditionTrue, DisruptionBudgetReason, message)
+	ReplaceHubCondition(&m.Status, *condition)
+}
+
+// clearPendingUpdate removes the PendingUpdate condition and next update window once no deployment has an
+// image update deferred
+func (r *ReconcileMultiClusterHub) clearPendingUpdate(m *operatorsv1.MultiClusterHub) {
+	if len(r.state(m).deferredUpdates) > 0 {
+		return
+	}
+	RemoveHubCondition(&m.Status, operatorsv1.PendingUpdate)
+	m.Status.NextUpdateWindow = nil
+}
+
+// deploymentChanged returns true if the desired deployment differs from the found one in its spec or annotations
+func deploymentChanged(found, desired *appsv1.Deployment) bool {
+	return !equality.Semantic.DeepEqual(found.Spec, desired.Spec) ||
+		!equality.Semantic.DeepEqual(found.Annotations, desired.Annotations)
+}
+
+// deferImageUpdates restores the container images of the found deployment in the desired deployment.
+// Returns true if any image change was deferred.
+func deferImageUpdates(found, desired *appsv1.Deployment) bool {
+	current := make(map[string]string)
+	for _, c := range found.Spec.Template.Spec.Containers {
+		current[c.Name] = c.Image
+	}
+
+	deferred := false
+	containers := desired.Spec.Template.Spec.Containers
+	for i := range containers {
+		if image, ok := current[containers[i].Name]; ok && image != containers[i].Image {
+			containers[i].Image = image
+			deferred = true
+		}
+	}
+	return deferred
+}
+
+// ensureNodePortsAvailable verifies the node ports requested for the hub's NodePort services are not already
+// assigned, either to another service on the cluster or to another service of this hub. A ConfigError condition
+// naming the conflicting ports is set and the request requeued rather than having the API server reject a service.
+func (r *ReconcileMultiClusterHub) ensureNodePortsAvailable(m *operatorsv1.MultiClusterHub, services []*corev1.Service) (*reconcile.Result, error) {
+	if utils.GetServiceType(m) != corev1.ServiceTypeNodePort {
+		removeConfigError(m, NodePortConflictReason)
 		return nil, nil
+	}
+
+	managed := make(map[types.NamespacedName]bool)
+	for _, s := range services {
+		managed[types.NamespacedName{Name: s.Name, Namespace: s.Namespace}] = true
+	}
+
+	svcList := &corev1.ServiceList{}
+	err := r.client.List(context.TODO(), svcList)
+	if err != nil {
+		log.Error(err, "Failed to list services")
+		return &reconcile.Result{}, err
+	}
+
+	// Track node ports already held by services outside this hub
+	assigned := make(map[int32]string)
+	for _, s := range svcList.Items {
+		if managed[types.NamespacedName{Name: s.Name, Namespace: s.Namespace}] {
+			continue
+		}
+		for _, p := range s.Spec.Ports {
+			if p.NodePort != 0 {
+				assigned[p.NodePort] = fmt.Sprintf("%s/%s", s.Namespace, s.Name)
+			}
+		}
+	}
+
+	var conflicts []string
+	for _, s := range services {
+		for _, p := range s.Spec.Ports {
+			if p.NodePort == 0 {
+				continue
+			}
+			if owner, ok := assigned[p.NodePort]; ok {
+				conflicts = append(conflicts, fmt.Sprintf("nodePort %d of service %s is already assigned to service %s", p.NodePort, s.Name, owner))
+				continue
+			}
+			assigned[p.NodePort] = fmt.Sprintf("%s/%s", s.Namespace, s.Name)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		message := strings.Join(conflicts, "; ")
+		log.Info("Requested node ports are unavailable", "Conflicts", message)
+		condition := NewHubCondition(operatorsv1.ConfigError, metav1.ConditionTrue, NodePortConflictReason, message)
+		SetHubCondition(&m.Status, *condition)
+		r.recorder.Event(m, corev1.EventTypeWarning, NodePortConflictReason, message)
+		return &reconcile.Result{RequeueAfter: resyncPeriod}, nil
+	}
+
+	removeConfigError(m, NodePortConflictReason)
+	return nil, nil
+}
+
+// checkResourceQuota compares the resources requested by the hub deployments against the resource quotas in the
+// hub namespace. Exceeding a quota only sets a QuotaExceeded condition; the rollout is still attempted.
+func (r *ReconcileMultiClusterHub) checkResourceQuota(m *operatorsv1.MultiClusterHub, deps []*appsv1.Deployment) {
+	quotaList := &corev1.ResourceQuotaList{}
+	err := r.client.List(context.TODO(), quotaList, client.InNamespace(m.Namespace))
+	if err != nil {
+		log.Error(err, "Failed to list resource quotas")
+		return
+	}
+
+	totals := deploymentResourceTotals(deps)
+
+	var exceeded []string
+	for _, quota := range quotaList.Items {
+		for name, hard := range quota.Spec.Hard {
+			requested, ok := totals[name]
+			if ok && requested.Cmp(hard) > 0 {
+				exceeded = append(exceeded, fmt.Sprintf("%s requested %s exceeds quota %s of %s", name, requested.String(), quota.Name, hard.String()))
+			}
+		}
+	}
+
+	if len(exceeded) > 0 {
+		sort.Strings(exceeded)
+		message := strings.Join(exceeded, "; ")
+		log.Info("Hub components exceed namespace resource quota", "Quota", message)
+		condition := NewHubCondition(operatorsv1.QuotaExceeded, metav1.ConditionTrue, ResourceQuotaExceededReason, message)
+		ReplaceHubCondition(&m.Status, *condition)
+		r.recorder.Event(m, corev1.EventTypeWarning, ResourceQuotaExceededReason, message)
+		return
+	}
+
+	RemoveHubCondition(&m.Status, operatorsv1.QuotaExceeded)
+}
+
+// checkImageArchitectures warns when a component image is not published for the architecture of a node the
+// hub can schedule onto. Registries are queried with the image pull secret within imageArchitectureTimeout. If
+// any query fails, the ArchitectureMismatch condition is left as it is.
+func (r *ReconcileMultiClusterHub) checkImageArchitectures(m *operatorsv1.MultiClusterHub,
+	architectures func(ctx context.Context, image string, keychain registry.Keychain) ([]string, error)) {
+	if !utils.VerifyImageArchitecture(m) {
+		RemoveHubCondition(&m.Status, operatorsv1.ArchitectureMismatch)
+		return
+	}
+
+	nodeList := &corev1.NodeList{}
+	err := r.client.List(context.TODO(), nodeList, client.MatchingLabels(m.Spec.NodeSelector))
+	if err != nil {
+		log.Error(err, "Failed to list nodes")
+		return
+	}
+	nodeArchs := map[string]bool{}
+	for _, n := range nodeList.Items {
+		if !n.Spec.Unschedulable && n.Status.NodeInfo.Architecture != "" {
+			nodeArchs[n.Status.NodeInfo.Architecture] = true
+		}
+	}
+
+	keys := make([]string, 0, len(r.CacheSpec.ImageOverrides))
+	for k := range r.CacheSpec.ImageOverrides {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	keychain := r.registryKeychain(m)
+	ctx, cancel := context.WithTimeout(context.TODO(), imageArchitectureTimeout)
+	defer cancel()
+
+	var mismatches []string
+	for _, component := range keys {
+		image := r.CacheSpec.ImageOverrides[component]
+		archs, err := architectures(ctx, image, keychain)
+		if err != nil {
+			log.Info("Could not query image architectures, leaving the ArchitectureMismatch condition unchanged", "Image", image, "error", err.Error())
+			return
+		}
+		supported := map[string]bool{}
+		for _, a := range archs {
+			supported[a] = true
+		}
+		var missing []string
+		for a := range nodeArchs {
+			if !supported[a] {
+				missing = append(missing, a)
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			mismatches = append(mismatches, fmt.Sprintf("%s does not support %s", component, strings.Join(missing, ", ")))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		message := strings.Join(mismatches, "; ")
+		log.Info("Component images do not support all node architectures", "Mismatches", message)
+		condition := NewHubCondition(operatorsv1.ArchitectureMismatch, metav1.ConditionTrue, ImageArchitectureMismatchReason, message)
+		ReplaceHubCondition(&m.Status, *condition)
+		r.recorder.Event(m, corev1.EventTypeWarning, ImageArchitectureMismatchReason, message)
+		return
+	}
+
+	RemoveHubCondition(&m.Status, operatorsv1.ArchitectureMismatch)
+}
 
+// registryKeychain returns the registry credentials in the hub image pull secret, or nil to query registries
+// anonymously
+func (r *ReconcileMultiClusterHub) registryKeychain(m *operatorsv1.MultiClusterHub) registry.Keychain {
+	if m.Spec.ImagePullSecret == "" {
+		return nil
+	}
+
+	secret := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: m.Spec.ImagePullSecret, Namespace: m.Namespace}, secret)
+	if err != nil {
+		log.Info("Could not get image pull secret, querying registries anonymously", "Name", m.Spec.ImagePullSecret, "error", err.Error())
+		return nil
+	}
+	keychain, err := registry.KeychainFromDockerConfig(secret.Data[corev1.DockerConfigJsonKey])
+	if err != nil {
+		log.Info("Could not read image pull secret, querying registries anonymously", "Name", m.Spec.ImagePullSecret, "error", err.Error())
+		return nil
+	}
+	return keychain
+}
+
+// checkImageDigests warns when the running pods of a deployment have reported different image digests
+// for longer than the digest grace period, which indicates a stuck rollout of a mutable tag
+func (r *ReconcileMultiClusterHub) checkImageDigests(m *operatorsv1.MultiClusterHub, deps []*appsv1.Deployment, now time.Time) {
+	state := r.state(m)
+	if !utils.VerifyImageDigests(m) {
+		state.digestsDivergedSince = nil
+		RemoveHubCondition(&m.Status, operatorsv1.DigestMismatch)
+		return
+	}
+	if state.digestsDivergedSince == nil {
+		state.digestsDivergedSince = map[string]time.Time{}
+	}
+
+	var stuck []string
+	for _, dep := range deps {
+		podList := &corev1.PodList{}
+		err := r.client.List(context.TODO(), podList, client.InNamespace(dep.Namespace), client.MatchingLabels(dep.Spec.Selector.MatchLabels))
+		if err != nil {
+			log.Error(err, "Failed to list pods", "Deployment", dep.Name)
+			return
+		}
+
+		if !podDigestsDiverge(podList.Items) {
+			delete(state.digestsDivergedSince, dep.Name)
+			continue
+		}
+		since, ok := state.digestsDivergedSince[dep.Name]
+		if !ok {
+			state.digestsDivergedSince[dep.Name] = now
+			continue
+		}
+		if now.Sub(since) >= digestGracePeriod {
+			stuck = append(stuck, dep.Name)
+		}
+	}
+
+	if len(stuck) > 0 {
+		message := fmt.Sprintf("Pods are running different image digests for more than %s: %s", digestGracePeriod, strings.Join(stuck, ", "))
+		log.Info(message)
+		condition := NewHubCondition(operatorsv1.DigestMismatch, metav1.ConditionTrue, ImageDigestMismatchReason, message)
+		ReplaceHubCondition(&m.Status, *condition)
+		r.recorder.Event(m, corev1.EventTypeWarning, ImageDigestMismatchReason, message)
+		return
+	}
+
+	RemoveHubCondition(&m.Status, operatorsv1.DigestMismatch)
+}
+
+// podDigestsDiverge returns true if running pods report more than one image digest for the same container
+func podDigestsDiverge(pods []corev1.Pod) bool {
+	digests := map[string]string{}
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.ImageID == "" {
+				continue
+			}
+			if seen, ok := digests[cs.Name]; ok && seen != cs.ImageID {
+				return true
+			}
+			digests[cs.Name] = cs.ImageID
+		}
+	}
+	return false
+}
+
+// deploymentResourceTotals sums the pods and container resources of the deployments across all replicas,
+// keyed by the resource names used in resource quotas
+func deploymentResourceTotals(deps []*appsv1.Deployment) corev1.ResourceList {
+	totals := corev1.ResourceList{}
+	add := func(name corev1.ResourceName, q resource.Quantity, replicas int64) {
+		sum := totals[name]
+		for i := int64(0); i < replicas; i++ {
+			sum.Add(q)
+		}
+		totals[name] = sum
+	}
+
+	for _, dep := range deps {
+		replicas := int64(1)
+		if dep.Spec.Replicas != nil {
+			replicas = int64(*dep.Spec.Replicas)
+		}
+		add(corev1.ResourcePods, *resource.NewQuantity(1, resource.DecimalSI), replicas)
+
+		for _, c := range dep.Spec.Template.Spec.Containers {
+			for name, q := range c.Resources.Requests {
+				switch name {
+				case corev1.ResourceCPU, corev1.ResourceMemory:
+					add(name, q, replicas)
+					add(corev1.ResourceName("requests."+string(name)), q, replicas)
+				}
+			}
+			for name, q := range c.Resources.Limits {
+				switch name {
+				case corev1.ResourceCPU, corev1.ResourceMemory:
+					add(corev1.ResourceName("limits."+string(name)), q, replicas)
+				}
+			}
+		}
+	}
+	return totals
+}
+
+// ensurePodSecurityLabels labels the hub namespace with the configured Pod Security admission level so
+// hub pods are not rejected on clusters that enforce a stricter default
+func (r *ReconcileMultiClusterHub) ensurePodSecurityLabels(m *operatorsv1.MultiClusterHub) (*reconcile.Result, error) {
+	nslog := log.WithValues("Namespace.Name", m.Namespace)
+
+	ns := &corev1.Namespace{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: m.Namespace}, ns)
+	if err != nil && errors.IsNotFound(err) {
+		return nil, nil
 	} else if err != nil {
-		// Error that isn't due to the apiService not existing
-		svlog.Error(err, "Failed to get apiService")
+		nslog.Error(err, "Failed to get namespace")
 		return &reconcile.Result{}, err
 	}
 
+	level := utils.GetPodSecurityLevel(m)
+	needsUpdate := false
+	for _, mode := range []string{"enforce", "warn", "audit"} {
+		key := "pod-security.kubernetes.io/" + mode
+		if ns.Labels[key] != level {
+			if ns.Labels == nil {
+				ns.Labels = map[string]string{}
+			}
+			ns.Labels[key] = level
+			needsUpdate = true
+		}
+	}
+	if !needsUpdate {
+		return nil, nil
+	}
+
+	nslog.Info("Updating namespace pod security labels", "Level", level)
+	err = r.client.Update(context.TODO(), ns)
+	if err != nil {
+		nslog.Error(err, "Failed to update namespace pod security labels")
+		return &reconcile.Result{}, err
+	}
 	return nil, nil
 }
 
-func (r *ReconcileMultiClusterHub) ensureChannel(m *operatorsv1.MultiClusterHub, u *unstructured.Unstructured) (*reconcile.Result, error) {
-	selog := log.WithValues("Channel.Namespace", u.GetNamespace(), "Channel.Name", u.GetName())
+// removeConfigError removes the ConfigError condition if it was set for the given reason
+func removeConfigError(m *operatorsv1.MultiClusterHub, reason string) {
+	if c := GetHubCondition(m.Status, operatorsv1.ConfigError); c != nil && c.Reason == reason {
+		RemoveHubCondition(&m.Status, operatorsv1.ConfigError)
+	}
+}
 
-	found := &unstructured.Unstructured{}
-	found.SetGroupVersionKind(schema.GroupVersionKind{
-		Group:   "apps.open-cluster-management.io",
-		Kind:    "Channel",
-		Version: "v1",
-	})
+// operatorNamespaceMismatch returns a message describing why the hub may not be reconciled when hubs are required
+// to be in the operator namespace, or an empty string if the hub may be reconciled
+func operatorNamespaceMismatch(m *operatorsv1.MultiClusterHub) string {
+	if !utils.RequireOperatorNamespace() {
+		return ""
+	}
+	operatorNs, err := utils.GetOperatorNamespace()
+	if err != nil {
+		return fmt.Sprintf("%s is set but the operator namespace is unknown: %s", utils.RequireOperatorNamespaceEnvVar, err.Error())
+	}
+	if m.Namespace != operatorNs {
+		return fmt.Sprintf("Multiclusterhub is in namespace %s but must be in the operator namespace %s", m.Namespace, operatorNs)
+	}
+	return ""
+}
+
+func (r *ReconcileMultiClusterHub) ensureAPIService(m *operatorsv1.MultiClusterHub, s *apiregistrationv1.APIService) (*reconcile.Result, error) {
+	svlog := log.WithValues("Service.Name", s.Name)
+
+	found := &apiregistrationv1.APIService{}
 	err := r.client.Get(context.TODO(), types.NamespacedName{
-		Name:      u.GetName(),
-		Namespace: m.Namespace,
+		Name: s.Name,
 	}, found)
 	if err != nil && errors.IsNotFound(err) {
 
-		// Create the Channel
-		err = r.client.Create(context.TODO(), u)
+		// Create the apiService
+		err = r.client.Create(context.TODO(), s)
+
 		if err != nil {
 			// Creation failed
-			selog.Error(err, "Failed to create new Channel")
+			svlog.Error(err, "Failed to create new apiService")
+			r.recorder.Eventf(m, corev1.EventTypeWarning, events.CreateFailedReason, "Failed to create APIService %s: %s", s.Name, err.Error())
 			return &reconcile.Result{}, err
 		}
 
 		// Creation was successful
-		selog.Info("Created a new Channel")
+		svlog.Info("Created a new apiService")
+		r.recorder.Eventf(m, corev1.EventTypeNormal, events.CreatedReason, "Created APIService %s", s.Name)
 		condition := NewHubCondition(operatorsv1.Progressing, metav1.ConditionTrue, NewComponentReason, "Created new resource")
 		SetHubCondition(&m.Status, *condition)
 		return nil, nil
 
 	} else if err != nil {
-		// Error that isn't due to the Channel not existing
-		selog.Error(err, "Failed to get Channel")
+		// Error that isn't due to the apiService not existing
+		svlog.Error(err, "Failed to get apiService")
 		return &reconcile.Result{}, err
 	}
 
 	return nil, nil
 }
 
-func (r *ReconcileMultiClusterHub) ensureSubscription(m *operatorsv1.MultiClusterHub, u *unstructured.Unstructured) (*reconcile.Result, error) {
+// validateFunc compares a found resource with the desired one, returning the resource to update and
+// whether an update is needed
+type validateFunc func(found, desired *unstructured.Unstructured) (*unstructured.Unstructured, bool)
+
+// ensureResource creates the desired resource if it doesn't exist, looking it up by the group, version,
+// kind and namespace of the desired object. When validate is non-nil, an existing resource is updated
+// whenever validate reports drift; otherwise it is left as is.
+func (r *ReconcileMultiClusterHub) ensureResource(m *operatorsv1.MultiClusterHub, u *unstructured.Unstructured, validate validateFunc) (*reconcile.Result, error) {
 	obLog := log.WithValues("Namespace", u.GetNamespace(), "Name", u.GetName(), "Kind", u.GetKind())
 
 	found := &unstructured.Unstructured{}
-	found.SetGroupVersionKind(schema.GroupVersionKind{
-		Group:   "apps.open-cluster-management.io",
-		Kind:    "Subscription",
-		Version: "v1",
-	})
+	found.SetGroupVersionKind(u.GroupVersionKind())
+
 	// Try to get API group instance
 	err := r.client.Get(context.TODO(), types.NamespacedName{
 		Name:      u.GetName(),
 		Namespace: u.GetNamespace(),
 	}, found)
 	if err != nil && errors.IsNotFound(err) {
+		// A desired object carrying server-set metadata was read from a previous instance and would be
+		// rejected on create
+		if u.GetUID() != "" || u.GetResourceVersion() != "" {
+			u.SetUID("")
+			u.SetResourceVersion("")
+		}
 
-		// Create the resource. Skip on unit test
-		if !utils.IsUnitTest() {
+		// Resource doesn't exist so create it. Subscriptions hold typed values the fake client
+		// cannot copy, so skip creating them on unit test
+		if !(utils.IsUnitTest() && u.GetKind() == "Subscription") {
 			err := r.client.Create(context.TODO(), u)
 			if err != nil {
 				// Creation failed
 				obLog.Error(err, "Failed to create new instance")
+				r.recorder.Eventf(m, corev1.EventTypeWarning, events.CreateFailedReason, "Failed to create %s %s: %s", u.GetKind(), u.GetName(), err.Error())
 				return &reconcile.Result{}, err
 			}
 		}
-
 		// Creation was successful
-		obLog.Info("Created new object")
+		obLog.Info("Created new resource")
+		r.recorder.Eventf(m, corev1.EventTypeNormal, events.CreatedReason, "Created %s %s", u.GetKind(), u.GetName())
 		condition := NewHubCondition(operatorsv1.Progressing, metav1.ConditionTrue, NewComponentReason, "Created new resource")
 		SetHubCondition(&m.Status, *condition)
 		return nil, nil
 
 	} else if err != nil {
 		// Error that isn't due to the resource not existing
-		obLog.Error(err, "Failed to get subscription")
+		obLog.Error(err, "Failed to get resource")
 		return &reconcile.Result{}, err
 	}
 
-	// Validate object based on type
-	updated, needsUpdate := subscription.Validate(found, u)
+	if r.checkOwnership(m, found.GetKind(), found) {
+		return nil, nil
+	}
+
+	if validate == nil {
+		return nil, nil
+	}
+	updated, needsUpdate := validate(found, u)
 	if needsUpdate {
-		obLog.Info("Updating subscription")
-		// Update the resource. Skip on unit test
+		obLog.Info("Updating resource")
 		err = r.client.Update(context.TODO(), updated)
 		if err != nil {
-			// Update failed
-			obLog.Error(err, "Failed to update object")
+			obLog.Error(err, "Failed to update resource")
+			r.recorder.Eventf(m, corev1.EventTypeWarning, events.UpdateFailedReason, "Failed to update %s %s: %s", u.GetKind(), u.GetName(), err.Error())
 			return &reconcile.Result{}, err
 		}
+		r.recorder.Eventf(m, corev1.EventTypeNormal, events.UpdatedReason, "Updated %s %s", u.GetKind(), u.GetName())
+		metrics.RecordDriftCorrection(u.GetKind(), u.GetName())
+	}
+	return nil, nil
+}
 
-		// Spec updated - return
-		return nil, nil
+func (r *ReconcileMultiClusterHub) ensureChannel(m *operatorsv1.MultiClusterHub, u *unstructured.Unstructured) (*reconcile.Result, error) {
+	return r.ensureResource(m, u, nil)
+}
+
+func (r *ReconcileMultiClusterHub) ensureRoute(m *operatorsv1.MultiClusterHub, u *unstructured.Unstructured) (*reconcile.Result, error) {
+	return r.ensureResource(m, u, route.Validate)
+}
+
+func (r *ReconcileMultiClusterHub) ensureServiceMonitor(m *operatorsv1.MultiClusterHub, u *unstructured.Unstructured) (*reconcile.Result, error) {
+	return r.ensureResource(m, u, servicemonitor.Validate)
+}
+
+// ensureRequiredOperators requeues until every ClusterServiceVersion the hub is configured to depend on has
+// reached the Succeeded phase
+func (r *ReconcileMultiClusterHub) ensureRequiredOperators(m *operatorsv1.MultiClusterHub) (*reconcile.Result, error) {
+	var notReady []string
+	for _, ref := range utils.GetRequiredOperators(m) {
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = m.Namespace
+		}
+
+		csv := &unstructured.Unstructured{}
+		csv.SetGroupVersionKind(schema.GroupVersionKind{
+			Group:   "operators.coreos.com",
+			Kind:    "ClusterServiceVersion",
+			Version: "v1alpha1",
+		})
+		err := r.client.Get(context.TODO(), types.NamespacedName{Name: ref.Name, Namespace: namespace}, csv)
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			// Missing CSVs, or OLM not being installed, leave the operator not ready
+			notReady = append(notReady, fmt.Sprintf("%s/%s", namespace, ref.Name))
+			continue
+		} else if err != nil {
+			log.Error(err, "Failed to get ClusterServiceVersion", "Name", ref.Name, "Namespace", namespace)
+			return &reconcile.Result{}, err
+		}
+		if phase, _, _ := unstructured.NestedString(csv.Object, "status", "phase"); phase != "Succeeded" {
+			notReady = append(notReady, fmt.Sprintf("%s/%s", namespace, ref.Name))
+		}
+	}
+
+	if len(notReady) > 0 {
+		message := fmt.Sprintf("Waiting for required operators to succeed: %s", strings.Join(notReady, ", "))
+		log.Info(message)
+		condition := NewHubCondition(operatorsv1.Progressing, metav1.ConditionFalse, OperatorsNotReadyReason, message)
+		SetHubCondition(&m.Status, *condition)
+		return &reconcile.Result{RequeueAfter: resyncPeriod}, nil
 	}
 
+	if c := GetHubCondition(m.Status, operatorsv1.Progressing); c != nil && c.Reason == OperatorsNotReadyReason {
+		condition := NewHubCondition(operatorsv1.Progressing, metav1.ConditionTrue, ReconcileReason, "Hub is reconciling.")
+		SetHubCondition(&m.Status, *condition)
+	}
 	return nil, nil
 }
 
-func (r *ReconcileMultiClusterHub) ensureUnstructuredResource(m *operatorsv1.MultiClusterHub, u *unstructured.Unstructured) (*reconcile.Result, error) {
-	obLog := log.WithValues("Namespace", u.GetNamespace(), "Name", u.GetName(), "Kind", u.GetKind())
+// ensureExternalChannel verifies the externally managed channel referenced by the hub exists. The channel
+// itself is left untouched.
+func (r *ReconcileMultiClusterHub) ensureExternalChannel(m *operatorsv1.MultiClusterHub) (*reconcile.Result, error) {
+	name, namespace := channel.Key(m)
+	chlog := log.WithValues("Channel.Namespace", namespace, "Channel.Name", name)
 
 	found := &unstructured.Unstructured{}
-	found.SetGroupVersionKind(u.GroupVersionKind())
-
-	// Try to get API group instance
+	found.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "apps.open-cluster-management.io",
+		Kind:    "Channel",
+		Version: "v1",
+	})
 	err := r.client.Get(context.TODO(), types.NamespacedName{
-		Name:      u.GetName(),
-		Namespace: u.GetNamespace(),
+		Name:      name,
+		Namespace: namespace,
 	}, found)
 	if err != nil && errors.IsNotFound(err) {
-		// Resource doesn't exist so create it
-		err := r.client.Create(context.TODO(), u)
-		if err != nil {
-			// Creation failed
-			obLog.Error(err, "Failed to create new instance")
-			return &reconcile.Result{}, err
-		}
-		// Creation was successful
-		obLog.Info("Created new resource")
-		condition := NewHubCondition(operatorsv1.Progressing, metav1.ConditionTrue, NewComponentReason, "Created new resource")
+		chlog.Info("Waiting for external channel to exist")
+		condition := NewHubCondition(operatorsv1.Progressing, metav1.ConditionFalse, ExternalChannelMissingReason,
+			fmt.Sprintf("Waiting for external channel %s", channel.Reference(m)))
 		SetHubCondition(&m.Status, *condition)
-		return nil, nil
-
+		return &reconcile.Result{RequeueAfter: resyncPeriod}, nil
 	} else if err != nil {
-		// Error that isn't due to the resource not existing
-		obLog.Error(err, "Failed to get resource")
+		chlog.Error(err, "Failed to get external Channel")
 		return &reconcile.Result{}, err
 	}
 
-	// Validate object based on name
-	var desired *unstructured.Unstructured
-	var needsUpdate bool
+	if c := GetHubCondition(m.Status, operatorsv1.Progressing); c != nil && c.Reason == ExternalChannelMissingReason {
+		condition := NewHubCondition(operatorsv1.Progressing, metav1.ConditionTrue, ReconcileReason, "Hub is reconciling.")
+		SetHubCondition(&m.Status, *condition)
+	}
+	return nil, nil
+}
 
-	switch found.GetKind() {
-	case "ClusterManager":
-		desired, needsUpdate = foundation.ValidateClusterManager(found, u)
-	default:
-		obLog.Info("Could not validate unstrucuted resource with type.", "Type", found.GetKind())
+// clusterScaledReplicas returns the replicas the ocm-controller and ocm-proxyserver need for the number of
+// managed clusters, or 0 when cluster scaling is disabled or the managed cluster API is not served
+func (r *ReconcileMultiClusterHub) clusterScaledReplicas(m *operatorsv1.MultiClusterHub) (int32, error) {
+	if !utils.ClusterScalingEnabled(m) {
+		return 0, nil
+	}
+	served, err := r.apiServed(schema.GroupVersion{Group: "cluster.open-cluster-management.io", Version: "v1"})
+	if err != nil || !served {
+		return 0, err
+	}
+
+	clusters := &unstructured.UnstructuredList{}
+	clusters.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "cluster.open-cluster-management.io",
+		Kind:    "ManagedClusterList",
+		Version: "v1",
+	})
+	if err := r.client.List(context.TODO(), clusters); err != nil {
+		return 0, err
+	}
+	return utils.ClusterScaledReplicas(m, len(clusters.Items)), nil
+}
+
+// ensureWebhookCABundle injects the CA of an externally provided webhook TLS secret into the ocm-webhook
+// configuration, requeuing until the secret exists. Rotating the CA updates the configuration.
+func (r *ReconcileMultiClusterHub) ensureWebhookCABundle(m *operatorsv1.MultiClusterHub) (*reconcile.Result, error) {
+	name := utils.GetWebhookTLSSecret(m)
+	if name == "" {
 		return nil, nil
 	}
+	whlog := log.WithValues("Secret.Namespace", m.Namespace, "Secret.Name", name)
 
-	if needsUpdate {
-		obLog.Info("Updating resource")
-		err = r.client.Update(context.TODO(), desired)
-		if err != nil {
-			obLog.Error(err, "Failed to update resource.")
-			return &reconcile.Result{}, err
+	secret := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      name,
+		Namespace: m.Namespace,
+	}, secret)
+	if err != nil && !errors.IsNotFound(err) {
+		whlog.Error(err, "Failed to get webhook TLS secret")
+		return &reconcile.Result{}, err
+	}
+	if errors.IsNotFound(err) || len(secret.Data["ca.crt"]) == 0 || len(secret.Data["tls.crt"]) == 0 || len(secret.Data["tls.key"]) == 0 {
+		whlog.Info("Waiting for webhook TLS secret")
+		condition := NewHubCondition(operatorsv1.Progressing, metav1.ConditionFalse, WebhookTLSSecretMissingReason,
+			fmt.Sprintf("Waiting for secret %s with ca.crt, tls.crt and tls.key", name))
+		SetHubCondition(&m.Status, *condition)
+		return &reconcile.Result{RequeueAfter: resyncPeriod}, nil
+	}
+
+	if c := GetHubCondition(m.Status, operatorsv1.Progressing); c != nil && c.Reason == WebhookTLSSecretMissingReason {
+		condition := NewHubCondition(operatorsv1.Progressing, metav1.ConditionTrue, ReconcileReason, "Hub is reconciling.")
+		SetHubCondition(&m.Status, *condition)
+	}
+
+	found := &unstructured.Unstructured{}
+	found.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "admissionregistration.k8s.io",
+		Kind:    "MutatingWebhookConfiguration",
+		Version: "v1",
+	})
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: "ocm-mutating-webhook"}, found)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		whlog.Error(err, "Failed to get MutatingWebhookConfiguration")
+		return &reconcile.Result{}, err
+	}
+
+	ca := base64.StdEncoding.EncodeToString(secret.Data["ca.crt"])
+	webhooks, _, _ := unstructured.NestedSlice(found.Object, "webhooks")
+	needsUpdate := false
+	for i := range webhooks {
+		webhook, ok := webhooks[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if bundle, _, _ := unstructured.NestedString(webhook, "clientConfig", "caBundle"); bundle != ca {
+			_ = unstructured.SetNestedField(webhook, ca, "clientConfig", "caBundle")
+			needsUpdate = true
 		}
 	}
+	if !needsUpdate {
+		return nil, nil
+	}
+
+	_ = unstructured.SetNestedSlice(found.Object, webhooks, "webhooks")
+	err = r.client.Update(context.TODO(), found)
+	if err != nil {
+		whlog.Error(err, "Failed to update MutatingWebhookConfiguration caBundle")
+		r.recorder.Eventf(m, corev1.EventTypeWarning, events.UpdateFailedReason, "Failed to update MutatingWebhookConfiguration %s: %s", found.GetName(), err.Error())
+		return &reconcile.Result{}, err
+	}
+	r.recorder.Eventf(m, corev1.EventTypeNormal, events.UpdatedReason, "Updated MutatingWebhookConfiguration %s", found.GetName())
+	metrics.RecordDriftCorrection("MutatingWebhookConfiguration", found.GetName())
 	return nil, nil
 }
 
-func (r *ReconcileMultiClusterHub) apiReady(gv schema.GroupVersion) (*reconcile.Result, error) {
+func (r *ReconcileMultiClusterHub) ensureSubscription(m *operatorsv1.MultiClusterHub, u *unstructured.Unstructured) (*reconcile.Result, error) {
+	if r.prerequisitesMissing(m, u.GetName()) {
+		return nil, nil
+	}
+	// A subscription without a kind is looked up as an app subscription, as this helper always has
+	if u.GroupVersionKind().Empty() {
+		u.SetGroupVersionKind(schema.GroupVersionKind{
+			Group:   "apps.open-cluster-management.io",
+			Kind:    "Subscription",
+			Version: "v1",
+		})
+	}
+	return r.ensureResource(m, u, subscription.Validate)
+}
+
+func (r *ReconcileMultiClusterHub) ensureUnstructuredResource(m *operatorsv1.MultiClusterHub, u *unstructured.Unstructured) (*reconcile.Result, error) {
+	// Validate object based on type
+	var validate validateFunc
+	switch u.GetKind() {
+	case "ClusterManager":
+		validate = foundation.ValidateClusterManager
+	default:
+		log.Info("Could not validate unstrucuted resource with type.", "Type", u.GetKind())
+	}
+	return r.ensureResource(m, u, validate)
+}
+
+// discoveryClient returns the discovery client shared across reconciles, creating it if needed
+func (r *ReconcileMultiClusterHub) discoveryClient() (discovery.DiscoveryInterface, error) {
+	if r.discovery != nil {
+		return r.discovery, nil
+	}
+
 	cfg, err := config.GetConfig()
 	if err != nil {
 		log.Error(err, "Failed to create rest config")
-		return &reconcile.Result{}, err
+		return nil, err
 	}
 
 	c, err := discovery.NewDiscoveryClientForConfig(cfg)
 	if err != nil {
 		log.Error(err, "Failed to create discovery client")
+		return nil, err
+	}
+	r.discovery = c
+	return c, nil
+}
+
+func (r *ReconcileMultiClusterHub) apiReady(gv schema.GroupVersion) (*reconcile.Result, error) {
+	c, err := r.discoveryClient()
+	if err != nil {
 		return &reconcile.Result{}, err
 	}
 
-	err = discovery.ServerSupportsVersion(c, gv)
+	served, err := r.groupVersionServed(c, gv)
 	if err != nil {
-		// Wait a little and try again
-		log.Info("Waiting for API group to be available", "API group", gv)
-		// condition := NewHubCondition(operatorsv1.Progressing, metav1.ConditionTrue, NewComponentReason, "Waiting for cert manager CRD availability")
-		// SetHubCondition(&m.Status, *condition)
+		log.Info("Failed to discover API groups", "Error", err.Error())
 		return &reconcile.Result{RequeueAfter: time.Second * 10}, nil
 	}
-	return nil, nil
+	if served {
+		return nil, nil
+	}
+	// Wait a little and try again
+	log.Info("Waiting for API group to be available", "API group", gv)
+	return &reconcile.Result{RequeueAfter: time.Second * 10}, nil
+}
+
+// groupVersionServed returns whether the API server serves the group version. A discovery failure is returned
+// as an error, never as the group version not being served
+func (r *ReconcileMultiClusterHub) groupVersionServed(c discovery.DiscoveryInterface, gv schema.GroupVersion) (bool, error) {
+	groups, err := c.ServerGroups()
+	if err != nil {
+		// Drop the client so a later reconcile rebuilds it rather than reusing a broken one
+		r.discovery = nil
+		return false, err
+	}
+
+	for _, v := range metav1.ExtractGroupVersions(groups) {
+		if v == gv.String() {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 func (r *ReconcileMultiClusterHub) copyPullSecret(m *operatorsv1.MultiClusterHub, newNS string) (*reconcile.Result, error) {
@@ -378,10 +1220,11 @@ func (r *ReconcileMultiClusterHub) copyPullSecret(m *operatorsv1.MultiClusterHub
 	}
 	utils.AddInstallerLabel(unstructuredPullSecret, m.Name, m.Namespace)
 
+	found := &v1.Secret{}
 	err = r.client.Get(context.TODO(), types.NamespacedName{
 		Name:      unstructuredPullSecret.GetName(),
 		Namespace: newNS,
-	}, unstructuredPullSecret)
+	}, found)
 
 	if err != nil && errors.IsNotFound(err) {
 		sublog.Info(fmt.Sprintf("Creating secret %s in namespace %s", unstructuredPullSecret.GetName(), utils.CertManagerNamespace))
@@ -390,7 +1233,37 @@ func (r *ReconcileMultiClusterHub) copyPullSecret(m *operatorsv1.MultiClusterHub
 			sublog.Error(err, "Failed to create secret")
 			return &reconcile.Result{}, err
 		}
+		return nil, nil
+	} else if err != nil {
+		sublog.Error(err, "Failed to get secret")
+		return &reconcile.Result{}, err
+	}
+
+	// Keep the copy in sync with the source so rotated credentials propagate
+	secretLabels := found.GetLabels()
+	if equality.Semantic.DeepEqual(found.Data, pullSecret.Data) &&
+		secretLabels["installer.name"] == m.Name && secretLabels["installer.namespace"] == m.Namespace {
+		return nil, nil
 	}
+
+	// Update the existing copy in place so its resourceVersion and UID are preserved
+	found.Data = pullSecret.Data
+	unstructuredFound, err := utils.CoreToUnstructured(found)
+	if err != nil {
+		sublog.Error(err, "Failed to unmarshal into unstructured object")
+		return &reconcile.Result{}, err
+	}
+	unstructuredFound.SetAPIVersion("v1")
+	unstructuredFound.SetKind("Secret")
+	utils.AddInstallerLabel(unstructuredFound, m.Name, m.Namespace)
+
+	sublog.Info(fmt.Sprintf("Updating secret %s in namespace %s", found.Name, newNS))
+	err = r.client.Update(context.TODO(), unstructuredFound)
+	if err != nil {
+		sublog.Error(err, "Failed to update secret")
+		return &reconcile.Result{}, err
+	}
+	metrics.RecordDriftCorrection("Secret", found.Name)
 	return nil, nil
 }
 
@@ -477,6 +1350,95 @@ func (r *ReconcileMultiClusterHub) maintainImageManifestConfigmap(mch *operators
 	return nil
 }
 
+// recordChartIndex records the charts served by the helm repo in a configmap for troubleshooting.
+// The index is refreshed periodically and errors never fail the reconcile.
+func (r *ReconcileMultiClusterHub) recordChartIndex(m *operatorsv1.MultiClusterHub, url string) {
+	found := &corev1.ConfigMap{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      helmrepo.IndexConfigMapName,
+		Namespace: m.Namespace,
+	}, found)
+	if err != nil && !errors.IsNotFound(err) {
+		log.Error(err, "Failed to get chart index configmap")
+		return
+	}
+	if err == nil && !helmrepo.IndexNeedsRefresh(found, time.Now()) {
+		return
+	}
+
+	charts, indexErr := helmrepo.ChartIndex(url)
+	if indexErr != nil {
+		log.Info("Could not read helm repo chart index", "URL", url, "error", indexErr.Error())
+		return
+	}
+
+	cm := helmrepo.IndexConfigMap(m, charts, time.Now())
+	if errors.IsNotFound(err) {
+		err = r.client.Create(context.TODO(), cm)
+	} else {
+		found.SetAnnotations(cm.GetAnnotations())
+		found.Data = cm.Data
+		err = r.client.Update(context.TODO(), found)
+	}
+	if err != nil {
+		log.Error(err, "Failed to record helm repo chart index")
+	}
+}
+
+// ensureSupportBundle gathers the hub state into a configmap when a support bundle is requested by annotation.
+// Errors never fail the reconcile.
+func (r *ReconcileMultiClusterHub) ensureSupportBundle(m *operatorsv1.MultiClusterHub) {
+	request := utils.GetSupportBundleRequest(m)
+	if request == "" {
+		return
+	}
+
+	found := &corev1.ConfigMap{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      supportbundle.ConfigMapName,
+		Namespace: m.Namespace,
+	}, found)
+	if err != nil && !errors.IsNotFound(err) {
+		log.Error(err, "Failed to get support bundle configmap")
+		return
+	}
+	if err == nil && supportbundle.Handled(found, request) {
+		return
+	}
+
+	// Events are read uncached so that gathering a bundle does not start a cluster-wide events informer
+	eventList := &corev1.EventList{}
+	if listErr := r.apiReader.List(context.TODO(), eventList, client.InNamespace(m.Namespace)); listErr != nil {
+		log.Error(listErr, "Failed to list events for support bundle")
+		return
+	}
+
+	cm, bundleErr := supportbundle.ConfigMap(m, request, supportbundle.Bundle{
+		Version: version.Version,
+		Hub:     m,
+		Config:  r.CacheSpec,
+		Events:  eventList.Items,
+	})
+	if bundleErr != nil {
+		log.Error(bundleErr, "Failed to generate support bundle")
+		return
+	}
+
+	if errors.IsNotFound(err) {
+		err = r.client.Create(context.TODO(), cm)
+	} else {
+		found.SetAnnotations(cm.GetAnnotations())
+		found.Data = cm.Data
+		err = r.client.Update(context.TODO(), found)
+	}
+	if err != nil {
+		log.Error(err, "Failed to save support bundle")
+		return
+	}
+	log.Info("Generated support bundle", "ConfigMap", supportbundle.ConfigMapName, "Request", request)
+	r.recorder.Eventf(m, corev1.EventTypeNormal, events.CreatedReason, "Generated support bundle in ConfigMap %s", supportbundle.ConfigMapName)
+}
+
 // listDeployments gets all deployments in the given namespaces
 func (r *ReconcileMultiClusterHub) listDeployments(namespaces []string) ([]*appsv1.Deployment, error) {
 	var ret []*appsv1.Deployment
@@ -5,9 +5,13 @@ package multiclusterhub
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,14 +21,28 @@ import (
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/foundation"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/helmrepo"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/manifest"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/registry"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/route"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/servicemonitor"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/subscription"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/supportbundle"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/utils"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -103,6 +121,168 @@ func Test_ensureDeployment(t *testing.T) {
 
 }
 
+func Test_ensureDeploymentConfigHash(t *testing.T) {
+	r, err := getTestReconciler(full_mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ocm-webhook-secret", Namespace: full_mch.Namespace},
+		Data:       map[string][]byte{"tls.crt": []byte("first")},
+	}
+	if err := r.client.Create(context.TODO(), secret); err != nil {
+		t.Fatalf("Failed to create secret: %v", err)
+	}
+
+	getHash := func() string {
+		dep := &appsv1.Deployment{}
+		err := r.client.Get(context.TODO(), types.NamespacedName{Name: foundation.WebhookName, Namespace: full_mch.Namespace}, dep)
+		if err != nil {
+			t.Fatalf("Could not find webhook deployment: %v", err)
+		}
+		return dep.Spec.Template.Annotations[utils.AnnotationConfigHash]
+	}
+
+	if _, err := r.ensureDeployment(full_mch, foundation.WebhookDeployment(full_mch, map[string]string{})); err != nil {
+		t.Fatalf("ensureDeployment() error = %v", err)
+	}
+	initial := getHash()
+	if initial == "" {
+		t.Fatalf("ensureDeployment() did not set the %s annotation", utils.AnnotationConfigHash)
+	}
+
+	secret.Data["tls.crt"] = []byte("second")
+	if err := r.client.Update(context.TODO(), secret); err != nil {
+		t.Fatalf("Failed to update secret: %v", err)
+	}
+	if _, err := r.ensureDeployment(full_mch, foundation.WebhookDeployment(full_mch, map[string]string{})); err != nil {
+		t.Fatalf("ensureDeployment() error = %v", err)
+	}
+	if getHash() == initial {
+		t.Errorf("ensureDeployment() did not update the %s annotation after a secret change", utils.AnnotationConfigHash)
+	}
+}
+
+func Test_ensureDeploymentUpdateWindow(t *testing.T) {
+	mch := full_mch.DeepCopy()
+	r, err := getTestReconciler(mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+
+	if _, err := r.ensureDeployment(mch, foundation.WebhookDeployment(mch, map[string]string{})); err != nil {
+		t.Fatalf("ensureDeployment() error = %v", err)
+	}
+
+	// Close the update window and request a new image along with a pull policy correction
+	now := time.Now().UTC()
+	closed := now.Add(-2 * time.Hour)
+	mch.Spec.Overrides = &operatorsv1.Overrides{
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		UpdateWindow: []operatorsv1.TimeWindow{
+			{Start: closed.Format("15:04"), End: closed.Add(time.Hour).Format("15:04")},
+		},
+	}
+	overrides := map[string]string{foundation.ImageKey: "quay.io/open-cluster-management/multicloud-manager:new"}
+	r.CacheSpec.ImageOverrides = overrides
+	if _, err := r.ensureDeployment(mch, foundation.WebhookDeployment(mch, overrides)); err != nil {
+		t.Fatalf("ensureDeployment() error = %v", err)
+	}
+
+	dep := &appsv1.Deployment{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: foundation.WebhookName, Namespace: mch.Namespace}, dep)
+	if err != nil {
+		t.Fatalf("Could not find webhook deployment: %v", err)
+	}
+	container := dep.Spec.Template.Spec.Containers[0]
+	if container.Image == foundation.Image(overrides) {
+		t.Errorf("ensureDeployment() updated the image outside of the update window")
+	}
+	if container.ImagePullPolicy != corev1.PullIfNotPresent {
+		t.Errorf("ensureDeployment() did not apply the pull policy correction")
+	}
+	if GetHubCondition(mch.Status, operatorsv1.PendingUpdate) == nil || mch.Status.NextUpdateWindow == nil {
+		t.Errorf("ensureDeployment() did not record the pending update")
+	}
+	r.clearPendingUpdate(mch)
+	if GetHubCondition(mch.Status, operatorsv1.PendingUpdate) == nil {
+		t.Errorf("clearPendingUpdate() cleared the pending update while it is still deferred")
+	}
+
+	// Reverting the image change leaves nothing pending
+	r.CacheSpec.ImageOverrides = map[string]string{}
+	if _, err := r.ensureDeployment(mch, foundation.WebhookDeployment(mch, map[string]string{})); err != nil {
+		t.Fatalf("ensureDeployment() error = %v", err)
+	}
+	r.clearPendingUpdate(mch)
+	if GetHubCondition(mch.Status, operatorsv1.PendingUpdate) != nil || mch.Status.NextUpdateWindow != nil {
+		t.Errorf("clearPendingUpdate() kept the pending update after the image change was reverted")
+	}
+
+	// An invalid update window does not defer the update
+	r.CacheSpec.ImageOverrides = overrides
+	mch.Spec.Overrides.UpdateWindow = append(mch.Spec.Overrides.UpdateWindow, operatorsv1.TimeWindow{Start: "1am", End: "5am"})
+	if _, err := r.ensureDeployment(mch, foundation.WebhookDeployment(mch, overrides)); err != nil {
+		t.Fatalf("ensureDeployment() error = %v", err)
+	}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: foundation.WebhookName, Namespace: mch.Namespace}, dep)
+	if err != nil {
+		t.Fatalf("Could not find webhook deployment: %v", err)
+	}
+	if dep.Spec.Template.Spec.Containers[0].Image != foundation.Image(overrides) {
+		t.Errorf("ensureDeployment() deferred the image update for an invalid update window")
+	}
+}
+
+func Test_ensurePVC(t *testing.T) {
+	mch := full_mch.DeepCopy()
+	mch.Spec.Overrides = &operatorsv1.Overrides{Persistence: &operatorsv1.PersistenceConfig{}}
+	r, err := getTestReconciler(mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+	key := types.NamespacedName{Name: helmrepo.CacheClaimName, Namespace: mch.Namespace}
+
+	// A created claim is pending until it binds
+	result, err := r.ensurePVC(mch, helmrepo.PersistentVolumeClaim(mch))
+	if err != nil || result == nil || result.RequeueAfter == 0 {
+		t.Fatalf("ensurePVC() = %v, %v, want a requeue for the new claim", result, err)
+	}
+	found := &corev1.PersistentVolumeClaim{}
+	if err := r.client.Get(context.TODO(), key, found); err != nil {
+		t.Fatalf("Failed to get PersistentVolumeClaim: %s", err)
+	}
+	found.Status.Phase = corev1.ClaimPending
+	if err := r.client.Update(context.TODO(), found); err != nil {
+		t.Fatalf("Failed to update PersistentVolumeClaim: %s", err)
+	}
+	if result, err = r.ensurePVC(mch, helmrepo.PersistentVolumeClaim(mch)); err != nil || result == nil {
+		t.Errorf("ensurePVC() = %v, %v, want a requeue while the claim is pending", result, err)
+	}
+
+	found.Status.Phase = corev1.ClaimBound
+	if err := r.client.Update(context.TODO(), found); err != nil {
+		t.Fatalf("Failed to update PersistentVolumeClaim: %s", err)
+	}
+	if result, err = r.ensurePVC(mch, helmrepo.PersistentVolumeClaim(mch)); result != nil || err != nil {
+		t.Errorf("ensurePVC() = %v, %v, want nil, nil once the claim is bound", result, err)
+	}
+
+	// Raising the size expands the claim
+	size := resource.MustParse("20Gi")
+	mch.Spec.Overrides.Persistence.Size = &size
+	if result, err = r.ensurePVC(mch, helmrepo.PersistentVolumeClaim(mch)); result != nil || err != nil {
+		t.Errorf("ensurePVC() = %v, %v, want nil, nil", result, err)
+	}
+	if err := r.client.Get(context.TODO(), key, found); err != nil {
+		t.Fatalf("Failed to get PersistentVolumeClaim: %s", err)
+	}
+	if got := found.Spec.Resources.Requests[corev1.ResourceStorage]; got.Cmp(size) != 0 {
+		t.Errorf("PersistentVolumeClaim size = %s, want %s", got.String(), size.String())
+	}
+}
+
 func Test_ensureService(t *testing.T) {
 	r, err := getTestReconciler(full_mch)
 	if err != nil {
@@ -162,6 +342,264 @@ func Test_ensureService(t *testing.T) {
 	}
 }
 
+func Test_ensureNodePortsAvailable(t *testing.T) {
+	mch := full_mch.DeepCopy()
+	mch.Spec.Overrides = &operatorsv1.Overrides{
+		ServiceType: corev1.ServiceTypeNodePort,
+		NodePorts: map[string]int32{
+			helmrepo.HelmRepoName:         30100,
+			foundation.WebhookName:        30101,
+			foundation.OCMProxyServerName: 30102,
+		},
+	}
+
+	other := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+		Spec: corev1.ServiceSpec{
+			Type:  corev1.ServiceTypeNodePort,
+			Ports: []corev1.ServicePort{{Port: 80, NodePort: 30101}},
+		},
+	}
+
+	t.Run("Ports available", func(t *testing.T) {
+		r, err := getTestReconciler(mch)
+		if err != nil {
+			t.Fatalf("Failed to create test reconciler")
+		}
+		result, err := r.ensureNodePortsAvailable(mch, []*corev1.Service{
+			helmrepo.Service(mch), foundation.WebhookService(mch), foundation.OCMProxyServerService(mch),
+		})
+		if result != nil || err != nil {
+			t.Fatalf("ensureNodePortsAvailable() = %v, %v, want nil, nil", result, err)
+		}
+		if GetHubCondition(mch.Status, operatorsv1.ConfigError) != nil {
+			t.Errorf("ensureNodePortsAvailable() set a ConfigError condition")
+		}
+	})
+
+	t.Run("Port held by another service", func(t *testing.T) {
+		m := mch.DeepCopy()
+		r, err := getTestReconciler(m)
+		if err != nil {
+			t.Fatalf("Failed to create test reconciler")
+		}
+		if err := r.client.Create(context.TODO(), other.DeepCopy()); err != nil {
+			t.Fatalf("Failed to create service: %v", err)
+		}
+		result, err := r.ensureNodePortsAvailable(m, []*corev1.Service{
+			helmrepo.Service(m), foundation.WebhookService(m), foundation.OCMProxyServerService(m),
+		})
+		if result == nil || err != nil {
+			t.Fatalf("ensureNodePortsAvailable() = %v, %v, want requeue", result, err)
+		}
+		c := GetHubCondition(m.Status, operatorsv1.ConfigError)
+		if c == nil || c.Reason != NodePortConflictReason {
+			t.Fatalf("ensureNodePortsAvailable() did not set a node port ConfigError condition")
+		}
+
+		// Once the conflict is resolved the condition is cleared
+		if err := r.client.Delete(context.TODO(), other.DeepCopy()); err != nil {
+			t.Fatalf("Failed to delete service: %v", err)
+		}
+		result, err = r.ensureNodePortsAvailable(m, []*corev1.Service{
+			helmrepo.Service(m), foundation.WebhookService(m), foundation.OCMProxyServerService(m),
+		})
+		if result != nil || err != nil {
+			t.Fatalf("ensureNodePortsAvailable() = %v, %v, want nil, nil", result, err)
+		}
+		if GetHubCondition(m.Status, operatorsv1.ConfigError) != nil {
+			t.Errorf("ensureNodePortsAvailable() did not clear the ConfigError condition")
+		}
+	})
+
+	t.Run("Port requested twice", func(t *testing.T) {
+		m := mch.DeepCopy()
+		m.Spec.Overrides.NodePorts[foundation.WebhookName] = 30100
+		r, err := getTestReconciler(m)
+		if err != nil {
+			t.Fatalf("Failed to create test reconciler")
+		}
+		result, err := r.ensureNodePortsAvailable(m, []*corev1.Service{
+			helmrepo.Service(m), foundation.WebhookService(m), foundation.OCMProxyServerService(m),
+		})
+		if result == nil || err != nil {
+			t.Fatalf("ensureNodePortsAvailable() = %v, %v, want requeue", result, err)
+		}
+	})
+}
+
+func Test_checkResourceQuota(t *testing.T) {
+	deps := []*appsv1.Deployment{
+		helmrepo.Deployment(full_mch, map[string]string{}),
+		foundation.WebhookDeployment(full_mch, map[string]string{}),
+	}
+
+	tests := []struct {
+		Name  string
+		Hard  corev1.ResourceList
+		Quota bool
+	}{
+		{
+			Name:  "Within quota",
+			Hard:  corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("10"), corev1.ResourcePods: resource.MustParse("20")},
+			Quota: false,
+		},
+		{
+			Name:  "CPU requests exceed quota",
+			Hard:  corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("10m")},
+			Quota: true,
+		},
+		{
+			Name:  "Memory limits exceed quota",
+			Hard:  corev1.ResourceList{corev1.ResourceLimitsMemory: resource.MustParse("1Mi")},
+			Quota: true,
+		},
+		{
+			Name:  "Pods exceed quota",
+			Hard:  corev1.ResourceList{corev1.ResourcePods: resource.MustParse("1")},
+			Quota: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			m := full_mch.DeepCopy()
+			r, err := getTestReconciler(m)
+			if err != nil {
+				t.Fatalf("Failed to create test reconciler")
+			}
+			quota := &corev1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: m.Namespace},
+				Spec:       corev1.ResourceQuotaSpec{Hard: tt.Hard},
+			}
+			if err := r.client.Create(context.TODO(), quota); err != nil {
+				t.Fatalf("Failed to create resource quota: %v", err)
+			}
+
+			r.checkResourceQuota(m, deps)
+			if got := GetHubCondition(m.Status, operatorsv1.QuotaExceeded) != nil; got != tt.Quota {
+				t.Errorf("checkResourceQuota() set QuotaExceeded = %v, want %v", got, tt.Quota)
+			}
+		})
+	}
+}
+
+func Test_recordChartIndex(t *testing.T) {
+	r, err := getTestReconciler(full_mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("entries:\n  grc:\n  - version: 2.2.0\n"))
+	}))
+	defer server.Close()
+
+	// Errors reading the index are not recorded
+	r.recordChartIndex(full_mch, server.URL+"\x7f")
+	cm := &corev1.ConfigMap{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: helmrepo.IndexConfigMapName, Namespace: full_mch.Namespace}, cm)
+	if !errors.IsNotFound(err) {
+		t.Fatalf("recordChartIndex() created a configmap for an unreadable index")
+	}
+
+	r.recordChartIndex(full_mch, server.URL)
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: helmrepo.IndexConfigMapName, Namespace: full_mch.Namespace}, cm)
+	if err != nil {
+		t.Fatalf("Could not find chart index configmap: %v", err)
+	}
+	if cm.Data["grc"] != "2.2.0" {
+		t.Errorf("recordChartIndex() data = %v, want grc=2.2.0", cm.Data)
+	}
+}
+
+func Test_checkImageArchitectures(t *testing.T) {
+	mch := full_mch.DeepCopy()
+	mch.Spec.Overrides = &operatorsv1.Overrides{VerifyImageArchitecture: true}
+	r, err := getTestReconciler(mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+	r.CacheSpec.ImageOverrides = map[string]string{
+		"multi":  "quay.io/open-cluster-management/multi:1.0",
+		"single": "quay.io/open-cluster-management/single:1.0",
+	}
+	mch.Spec.ImagePullSecret = "pull-secret"
+	auth := base64.StdEncoding.EncodeToString([]byte("user:pass"))
+	pullSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "pull-secret", Namespace: mch.Namespace},
+		Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(fmt.Sprintf(`{"auths":{"quay.io":{"auth":"%s"}}}`, auth))},
+	}
+	if err := r.client.Create(context.TODO(), pullSecret); err != nil {
+		t.Fatalf("Failed to create pull secret: %v", err)
+	}
+	var queryErr error
+	architectures := func(ctx context.Context, image string, keychain registry.Keychain) ([]string, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Errorf("checkImageArchitectures() queried %s without a deadline", image)
+		}
+		if keychain["quay.io"] != (registry.Credential{Username: "user", Password: "pass"}) {
+			t.Errorf("checkImageArchitectures() keychain = %v, want the pull secret credentials", keychain)
+		}
+		if queryErr != nil {
+			return nil, queryErr
+		}
+		if strings.Contains(image, "multi") {
+			return []string{"amd64", "arm64"}, nil
+		}
+		return []string{"amd64"}, nil
+	}
+
+	for _, n := range []struct {
+		name          string
+		arch          string
+		unschedulable bool
+	}{
+		{"amd64-node", "amd64", false},
+		{"arm64-node", "arm64", false},
+		{"s390x-node", "s390x", true},
+	} {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: n.name},
+			Spec:       corev1.NodeSpec{Unschedulable: n.unschedulable},
+			Status:     corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{Architecture: n.arch}},
+		}
+		if err := r.client.Create(context.TODO(), node); err != nil {
+			t.Fatalf("Failed to create node: %v", err)
+		}
+	}
+
+	r.checkImageArchitectures(mch, architectures)
+	c := GetHubCondition(mch.Status, operatorsv1.ArchitectureMismatch)
+	if c == nil {
+		t.Fatalf("checkImageArchitectures() did not set the ArchitectureMismatch condition")
+	}
+	if want := "single does not support arm64"; c.Message != want {
+		t.Errorf("checkImageArchitectures() message = %v, want %v", c.Message, want)
+	}
+
+	// A failed query neither confirms nor clears the condition
+	queryErr = fmt.Errorf("registry unavailable")
+	r.checkImageArchitectures(mch, architectures)
+	if c := GetHubCondition(mch.Status, operatorsv1.ArchitectureMismatch); c == nil || c.Message != "single does not support arm64" {
+		t.Errorf("checkImageArchitectures() condition = %v, want it unchanged after a failed query", c)
+	}
+	queryErr = nil
+
+	// The message follows the current mismatches
+	r.CacheSpec.ImageOverrides = map[string]string{"other": "quay.io/open-cluster-management/other:1.0"}
+	r.checkImageArchitectures(mch, architectures)
+	if c := GetHubCondition(mch.Status, operatorsv1.ArchitectureMismatch); c == nil || c.Message != "other does not support arm64" {
+		t.Errorf("checkImageArchitectures() condition = %v, want the message updated", c)
+	}
+
+	mch.Spec.Overrides.VerifyImageArchitecture = false
+	r.checkImageArchitectures(mch, architectures)
+	if GetHubCondition(mch.Status, operatorsv1.ArchitectureMismatch) != nil {
+		t.Errorf("checkImageArchitectures() did not clear the condition when disabled")
+	}
+}
+
 func Test_ensureChannel(t *testing.T) {
 	r, err := getTestReconciler(full_mch)
 	if err != nil {
@@ -199,6 +637,643 @@ func Test_ensureChannel(t *testing.T) {
 	}
 }
 
+func Test_ensureDeploymentDisruptionBudget(t *testing.T) {
+	mch := full_mch.DeepCopy()
+	r, err := getTestReconciler(mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+
+	deployments := func() []*appsv1.Deployment {
+		return []*appsv1.Deployment{
+			foundation.WebhookDeployment(mch, map[string]string{}),
+			foundation.OCMProxyServerDeployment(mch, map[string]string{}),
+		}
+	}
+	ensureAll := func() {
+		for _, dep := range deployments() {
+			if _, err := r.ensureDeployment(mch, dep); err != nil {
+				t.Fatalf("ensureDeployment() error = %v", err)
+			}
+		}
+	}
+	ensureAll()
+
+	minAvailable := intstr.FromInt(2)
+	pdb := &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "hub-pdb", Namespace: mch.Namespace},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MinAvailable: &minAvailable,
+			Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key:      "app",
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   []string{foundation.WebhookName, foundation.OCMProxyServerName},
+			}}},
+		},
+	}
+	if err := r.client.Create(context.TODO(), pdb); err != nil {
+		t.Fatalf("Failed to create PodDisruptionBudget: %v", err)
+	}
+
+	getReplicas := func(name string) int32 {
+		found := &appsv1.Deployment{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: mch.Namespace}, found); err != nil {
+			t.Fatalf("Failed to get deployment: %v", err)
+		}
+		return *found.Spec.Replicas
+	}
+
+	recorder := record.NewFakeRecorder(10)
+	r.recorder = recorder
+	mch.Spec.AvailabilityConfig = operatorsv1.HABasic
+	ensureAll()
+	ensureAll()
+	for _, name := range []string{foundation.WebhookName, foundation.OCMProxyServerName} {
+		if got := getReplicas(name); got != 2 {
+			t.Errorf("ensureDeployment() %s replicas = %d, want 2 while blocked by PodDisruptionBudget", name, got)
+		}
+	}
+	if got := len(recorder.Events); got != 2 {
+		t.Errorf("ensureDeployment() emitted %d events, want one per newly blocked deployment", got)
+	}
+	c := GetHubCondition(mch.Status, operatorsv1.ScaleDownBlocked)
+	if c == nil || c.Reason != DisruptionBudgetReason {
+		t.Fatalf("ensureDeployment() did not set the %s condition", operatorsv1.ScaleDownBlocked)
+	}
+	if !strings.Contains(c.Message, foundation.WebhookName) || !strings.Contains(c.Message, foundation.OCMProxyServerName) {
+		t.Errorf("ensureDeployment() condition message = %q, want both blocked deployments", c.Message)
+	}
+
+	if err := r.client.Delete(context.TODO(), pdb); err != nil {
+		t.Fatalf("Failed to delete PodDisruptionBudget: %v", err)
+	}
+	if _, err := r.ensureDeployment(mch, foundation.WebhookDeployment(mch, map[string]string{})); err != nil {
+		t.Fatalf("ensureDeployment() error = %v", err)
+	}
+	if got := getReplicas(foundation.WebhookName); got != 1 {
+		t.Errorf("ensureDeployment() replicas = %d, want 1", got)
+	}
+	c = GetHubCondition(mch.Status, operatorsv1.ScaleDownBlocked)
+	if c == nil || strings.Contains(c.Message, foundation.WebhookName) || !strings.Contains(c.Message, foundation.OCMProxyServerName) {
+		t.Errorf("ensureDeployment() condition = %v, want only %s blocked", c, foundation.OCMProxyServerName)
+	}
+
+	ensureAll()
+	if c := GetHubCondition(mch.Status, operatorsv1.ScaleDownBlocked); c != nil {
+		t.Errorf("ensureDeployment() did not clear the %s condition", operatorsv1.ScaleDownBlocked)
+	}
+}
+
+func Test_ensureDeploymentEnvFrom(t *testing.T) {
+	optional := true
+	mch := full_mch.DeepCopy()
+	mch.Spec.Overrides = &operatorsv1.Overrides{EnvFrom: map[string][]corev1.EnvFromSource{
+		foundation.WebhookName: {
+			{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "proxy-env"}}},
+			{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "extra-env"}, Optional: &optional}},
+		},
+	}}
+	r, err := getTestReconciler(mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+
+	result, err := r.ensureDeployment(mch, foundation.WebhookDeployment(mch, map[string]string{}))
+	if result == nil || err != nil {
+		t.Fatalf("ensureDeployment() = %v, %v, want requeue for missing configmap", result, err)
+	}
+	if c := GetHubCondition(mch.Status, operatorsv1.ConfigError); c == nil || c.Reason != EnvFromSourceMissingReason {
+		t.Fatalf("ensureDeployment() did not set the %s condition", EnvFromSourceMissingReason)
+	}
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "proxy-env", Namespace: mch.Namespace}}
+	if err := r.client.Create(context.TODO(), cm); err != nil {
+		t.Fatalf("Failed to create configmap: %v", err)
+	}
+	if _, err := r.ensureDeployment(mch, foundation.WebhookDeployment(mch, map[string]string{})); err != nil {
+		t.Fatalf("ensureDeployment() error = %v", err)
+	}
+	if c := GetHubCondition(mch.Status, operatorsv1.ConfigError); c != nil && c.Reason == EnvFromSourceMissingReason {
+		t.Errorf("ensureDeployment() did not clear the %s condition", EnvFromSourceMissingReason)
+	}
+
+	// Drop the sources in the cluster and expect them restored
+	found := &appsv1.Deployment{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: foundation.WebhookName, Namespace: mch.Namespace}, found); err != nil {
+		t.Fatalf("Failed to get deployment: %v", err)
+	}
+	found.Spec.Template.Spec.Containers[0].EnvFrom = nil
+	if err := r.client.Update(context.TODO(), found); err != nil {
+		t.Fatalf("Failed to update deployment: %v", err)
+	}
+	if _, err := r.ensureDeployment(mch, foundation.WebhookDeployment(mch, map[string]string{})); err != nil {
+		t.Fatalf("ensureDeployment() error = %v", err)
+	}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: foundation.WebhookName, Namespace: mch.Namespace}, found); err != nil {
+		t.Fatalf("Failed to get deployment: %v", err)
+	}
+	if got := found.Spec.Template.Spec.Containers[0].EnvFrom; !reflect.DeepEqual(got, mch.Spec.Overrides.EnvFrom[foundation.WebhookName]) {
+		t.Errorf("ensureDeployment() envFrom = %v, want %v", got, mch.Spec.Overrides.EnvFrom[foundation.WebhookName])
+	}
+}
+
+func Test_operatorNamespaceMismatch(t *testing.T) {
+	defer os.Unsetenv(utils.RequireOperatorNamespaceEnvVar)
+	defer os.Unsetenv("POD_NAMESPACE")
+
+	tests := []struct {
+		name       string
+		require    string
+		operatorNs string
+		mismatch   bool
+	}{
+		{name: "Not required", require: "", operatorNs: "other", mismatch: false},
+		{name: "Same namespace", require: "true", operatorNs: full_mch.Namespace, mismatch: false},
+		{name: "Different namespace", require: "true", operatorNs: "other", mismatch: true},
+		{name: "Operator namespace unknown", require: "true", operatorNs: "", mismatch: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(utils.RequireOperatorNamespaceEnvVar, tt.require)
+			os.Unsetenv("POD_NAMESPACE")
+			if tt.operatorNs != "" {
+				os.Setenv("POD_NAMESPACE", tt.operatorNs)
+			}
+			if message := operatorNamespaceMismatch(full_mch); (message != "") != tt.mismatch {
+				t.Errorf("operatorNamespaceMismatch() = %q, want mismatch %v", message, tt.mismatch)
+			}
+		})
+	}
+}
+
+func Test_ensurePodSecurityLabels(t *testing.T) {
+	mch := full_mch.DeepCopy()
+	r, err := getTestReconciler(mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   mch.Namespace,
+		Labels: map[string]string{"pod-security.kubernetes.io/enforce": "restricted"},
+	}}
+	if err := r.client.Create(context.TODO(), ns); err != nil {
+		t.Fatalf("Failed to create namespace: %v", err)
+	}
+
+	getLabels := func() map[string]string {
+		found := &corev1.Namespace{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: mch.Namespace}, found); err != nil {
+			t.Fatalf("Failed to get namespace: %v", err)
+		}
+		return found.Labels
+	}
+
+	if result, err := r.ensurePodSecurityLabels(mch); result != nil || err != nil {
+		t.Fatalf("ensurePodSecurityLabels() = %v, %v, want nil, nil", result, err)
+	}
+	for _, mode := range []string{"enforce", "warn", "audit"} {
+		if got := getLabels()["pod-security.kubernetes.io/"+mode]; got != utils.DefaultPodSecurityLevel {
+			t.Errorf("ensurePodSecurityLabels() %s level = %s, want %s", mode, got, utils.DefaultPodSecurityLevel)
+		}
+	}
+
+	mch.Spec.Overrides = &operatorsv1.Overrides{PodSecurityLevel: "privileged"}
+	if result, err := r.ensurePodSecurityLabels(mch); result != nil || err != nil {
+		t.Fatalf("ensurePodSecurityLabels() = %v, %v, want nil, nil", result, err)
+	}
+	if got := getLabels()["pod-security.kubernetes.io/enforce"]; got != "privileged" {
+		t.Errorf("ensurePodSecurityLabels() enforce level = %s, want privileged", got)
+	}
+}
+
+func Test_ensureDeploymentClusterScaling(t *testing.T) {
+	os.Setenv("UNIT_TEST", "true")
+	defer os.Unsetenv("UNIT_TEST")
+
+	mch := full_mch.DeepCopy()
+	mch.Spec.Overrides = &operatorsv1.Overrides{ClusterScaling: &operatorsv1.ClusterScaling{ClustersPerReplica: 2, MaxReplicas: 4}}
+	r, err := getTestReconciler(mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+
+	// The fake client tracks unstructured lists only for kinds known to its scheme
+	clusterGV := schema.GroupVersion{Group: "cluster.open-cluster-management.io", Version: "v1"}
+	r.scheme.AddKnownTypeWithName(clusterGV.WithKind("ManagedCluster"), &unstructured.Unstructured{})
+	r.scheme.AddKnownTypeWithName(clusterGV.WithKind("ManagedClusterList"), &unstructured.UnstructuredList{})
+
+	for i := 0; i < 5; i++ {
+		cluster := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "cluster.open-cluster-management.io/v1",
+			"kind":       "ManagedCluster",
+			"metadata":   map[string]interface{}{"name": fmt.Sprintf("cluster-%d", i)},
+		}}
+		if err := r.client.Create(context.TODO(), cluster); err != nil {
+			t.Fatalf("Failed to create ManagedCluster: %v", err)
+		}
+	}
+
+	r.CacheSpec.ClusterScaledReplicas, err = r.clusterScaledReplicas(mch)
+	if err != nil || r.CacheSpec.ClusterScaledReplicas != 3 {
+		t.Fatalf("clusterScaledReplicas() = %d, %v, want 3", r.CacheSpec.ClusterScaledReplicas, err)
+	}
+
+	replicas := func(name string) int32 {
+		found := &appsv1.Deployment{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: mch.Namespace}, found); err != nil {
+			t.Fatalf("Failed to get deployment %s: %v", name, err)
+		}
+		return *found.Spec.Replicas
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := r.ensureDeployment(mch, foundation.OCMControllerDeployment(mch, map[string]string{})); err != nil {
+			t.Fatalf("ensureDeployment() error = %v", err)
+		}
+		if _, err := r.ensureDeployment(mch, foundation.WebhookDeployment(mch, map[string]string{})); err != nil {
+			t.Fatalf("ensureDeployment() error = %v", err)
+		}
+	}
+	if got := replicas(foundation.OCMControllerName); got != 3 {
+		t.Errorf("ensureDeployment() %s replicas = %d, want 3", foundation.OCMControllerName, got)
+	}
+	if got := replicas(foundation.WebhookName); got != 2 {
+		t.Errorf("ensureDeployment() %s replicas = %d, want the availability config count", foundation.WebhookName, got)
+	}
+
+	// Disabling cluster scaling returns to the availability config
+	r.CacheSpec.ClusterScaledReplicas = 0
+	if _, err := r.ensureDeployment(mch, foundation.OCMControllerDeployment(mch, map[string]string{})); err != nil {
+		t.Fatalf("ensureDeployment() error = %v", err)
+	}
+	if got := replicas(foundation.OCMControllerName); got != 2 {
+		t.Errorf("ensureDeployment() %s replicas = %d, want 2", foundation.OCMControllerName, got)
+	}
+}
+
+func Test_ensureWebhookCABundle(t *testing.T) {
+	mch := full_mch.DeepCopy()
+	mch.Spec.Overrides = &operatorsv1.Overrides{WebhookTLSSecret: "external-webhook-tls"}
+	r, err := getTestReconciler(mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+
+	result, err := r.ensureWebhookCABundle(mch)
+	if result == nil || err != nil {
+		t.Fatalf("ensureWebhookCABundle() = %v, %v, want requeue for missing secret", result, err)
+	}
+	if c := GetHubCondition(mch.Status, operatorsv1.Progressing); c == nil || c.Reason != WebhookTLSSecretMissingReason {
+		t.Errorf("ensureWebhookCABundle() did not report the missing secret: %v", c)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "external-webhook-tls", Namespace: mch.Namespace},
+		Data: map[string][]byte{
+			"ca.crt":  []byte("ca-1"),
+			"tls.crt": []byte("cert"),
+			"tls.key": []byte("key"),
+		},
+	}
+	if err := r.client.Create(context.TODO(), secret); err != nil {
+		t.Fatalf("Failed to create secret: %v", err)
+	}
+	webhookConfig := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "admissionregistration.k8s.io/v1",
+		"kind":       "MutatingWebhookConfiguration",
+		"metadata":   map[string]interface{}{"name": "ocm-mutating-webhook"},
+		"webhooks": []interface{}{map[string]interface{}{
+			"name":         "ocm.mutating.webhook.admission.open-cluster-management.io",
+			"clientConfig": map[string]interface{}{"caBundle": "abc"},
+		}},
+	}}
+	if err := r.client.Create(context.TODO(), webhookConfig); err != nil {
+		t.Fatalf("Failed to create MutatingWebhookConfiguration: %v", err)
+	}
+
+	caBundle := func() string {
+		found := &unstructured.Unstructured{}
+		found.SetGroupVersionKind(webhookConfig.GroupVersionKind())
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "ocm-mutating-webhook"}, found); err != nil {
+			t.Fatalf("Failed to get MutatingWebhookConfiguration: %v", err)
+		}
+		webhooks, _, _ := unstructured.NestedSlice(found.Object, "webhooks")
+		bundle, _, _ := unstructured.NestedString(webhooks[0].(map[string]interface{}), "clientConfig", "caBundle")
+		return bundle
+	}
+
+	for _, ca := range []string{"ca-1", "ca-2"} {
+		secret.Data["ca.crt"] = []byte(ca)
+		if err := r.client.Update(context.TODO(), secret); err != nil {
+			t.Fatalf("Failed to update secret: %v", err)
+		}
+		if result, err := r.ensureWebhookCABundle(mch); result != nil || err != nil {
+			t.Fatalf("ensureWebhookCABundle() = %v, %v, want nil", result, err)
+		}
+		if got, want := caBundle(), base64.StdEncoding.EncodeToString([]byte(ca)); got != want {
+			t.Errorf("ensureWebhookCABundle() caBundle = %s, want %s", got, want)
+		}
+	}
+	if c := GetHubCondition(mch.Status, operatorsv1.Progressing); c != nil && c.Reason == WebhookTLSSecretMissingReason {
+		t.Errorf("ensureWebhookCABundle() did not clear the missing secret condition")
+	}
+}
+
+// noMatchClient fails every Get as if the kind were not served by the cluster
+type noMatchClient struct {
+	client.Client
+}
+
+func (c noMatchClient) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	return &meta.NoKindMatchError{GroupKind: obj.GetObjectKind().GroupVersionKind().GroupKind()}
+}
+
+func Test_ensureRequiredOperators(t *testing.T) {
+	mch := full_mch.DeepCopy()
+	mch.Spec.Overrides = &operatorsv1.Overrides{RequiredOperators: []operatorsv1.OperatorReference{
+		{Name: "multicluster-operators-subscription.v2.2.0", Namespace: "openshift-operators"},
+	}}
+	r, err := getTestReconciler(mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+
+	result, err := r.ensureRequiredOperators(mch)
+	if result == nil || err != nil {
+		t.Fatalf("ensureRequiredOperators() = %v, %v, want requeue for missing operator", result, err)
+	}
+
+	cached := r.client
+	r.client = noMatchClient{cached}
+	mch.Status.HubConditions = nil
+	result, err = r.ensureRequiredOperators(mch)
+	if result == nil || err != nil {
+		t.Fatalf("ensureRequiredOperators() = %v, %v, want requeue when OLM is not installed", result, err)
+	}
+	if c := GetHubCondition(mch.Status, operatorsv1.Progressing); c == nil || c.Reason != OperatorsNotReadyReason {
+		t.Fatalf("ensureRequiredOperators() condition = %v, want %s when OLM is not installed", c, OperatorsNotReadyReason)
+	}
+	r.client = cached
+
+	csv := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "operators.coreos.com/v1alpha1",
+		"kind":       "ClusterServiceVersion",
+		"metadata": map[string]interface{}{
+			"name":      "multicluster-operators-subscription.v2.2.0",
+			"namespace": "openshift-operators",
+		},
+		"status": map[string]interface{}{"phase": "Installing"},
+	}}
+	if err := r.client.Create(context.TODO(), csv); err != nil {
+		t.Fatalf("Failed to create ClusterServiceVersion: %v", err)
+	}
+	result, err = r.ensureRequiredOperators(mch)
+	if result == nil || err != nil {
+		t.Fatalf("ensureRequiredOperators() = %v, %v, want requeue for installing operator", result, err)
+	}
+	c := GetHubCondition(mch.Status, operatorsv1.Progressing)
+	if c == nil || c.Reason != OperatorsNotReadyReason || !strings.Contains(c.Message, "openshift-operators/multicluster-operators-subscription.v2.2.0") {
+		t.Fatalf("ensureRequiredOperators() condition = %v, want %s naming the operator", c, OperatorsNotReadyReason)
+	}
+
+	_ = unstructured.SetNestedField(csv.Object, "Succeeded", "status", "phase")
+	if err := r.client.Update(context.TODO(), csv); err != nil {
+		t.Fatalf("Failed to update ClusterServiceVersion: %v", err)
+	}
+	result, err = r.ensureRequiredOperators(mch)
+	if result != nil || err != nil {
+		t.Fatalf("ensureRequiredOperators() = %v, %v, want nil, nil", result, err)
+	}
+	if c := GetHubCondition(mch.Status, operatorsv1.Progressing); c != nil && c.Reason == OperatorsNotReadyReason {
+		t.Errorf("ensureRequiredOperators() did not clear the %s condition", OperatorsNotReadyReason)
+	}
+}
+
+func Test_ensureSupportBundle(t *testing.T) {
+	mch := full_mch.DeepCopy()
+	mch.SetAnnotations(map[string]string{utils.AnnotationSupportBundle: "case-1"})
+	r, err := getTestReconciler(mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+
+	getBundle := func() *corev1.ConfigMap {
+		cm := &corev1.ConfigMap{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: supportbundle.ConfigMapName, Namespace: mch.Namespace}, cm); err != nil {
+			t.Fatalf("Failed to get support bundle: %v", err)
+		}
+		return cm
+	}
+
+	r.ensureSupportBundle(mch)
+	if !supportbundle.Handled(getBundle(), "case-1") {
+		t.Fatalf("ensureSupportBundle() did not generate the requested bundle")
+	}
+
+	mch.SetAnnotations(map[string]string{utils.AnnotationSupportBundle: "case-2"})
+	r.ensureSupportBundle(mch)
+	if !supportbundle.Handled(getBundle(), "case-2") {
+		t.Errorf("ensureSupportBundle() did not regenerate the bundle for a new request")
+	}
+}
+
+func Test_checkImageDigests(t *testing.T) {
+	mch := full_mch.DeepCopy()
+	mch.Spec.Overrides = &operatorsv1.Overrides{VerifyImageDigests: true}
+	r, err := getTestReconciler(mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+
+	dep := foundation.WebhookDeployment(mch, map[string]string{})
+	for i, digest := range []string{"sha256:aaa", "sha256:bbb"} {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%d", foundation.WebhookName, i),
+				Namespace: mch.Namespace,
+				Labels:    dep.Spec.Selector.MatchLabels,
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:    foundation.WebhookName,
+					ImageID: "quay.io/open-cluster-management/multicloud-manager@" + digest,
+				}},
+			},
+		}
+		if err := r.client.Create(context.TODO(), pod); err != nil {
+			t.Fatalf("Failed to create pod: %v", err)
+		}
+	}
+
+	now := time.Now()
+	r.checkImageDigests(mch, []*appsv1.Deployment{dep}, now)
+	if c := GetHubCondition(mch.Status, operatorsv1.DigestMismatch); c != nil {
+		t.Errorf("checkImageDigests() reported a mismatch within the grace period")
+	}
+
+	r.checkImageDigests(mch, []*appsv1.Deployment{dep}, now.Add(digestGracePeriod))
+	c := GetHubCondition(mch.Status, operatorsv1.DigestMismatch)
+	if c == nil || !strings.Contains(c.Message, foundation.WebhookName) {
+		t.Fatalf("checkImageDigests() condition = %v, want mismatch naming %s", c, foundation.WebhookName)
+	}
+
+	mch.Spec.Overrides.VerifyImageDigests = false
+	r.checkImageDigests(mch, []*appsv1.Deployment{dep}, now.Add(digestGracePeriod))
+	if c := GetHubCondition(mch.Status, operatorsv1.DigestMismatch); c != nil {
+		t.Errorf("checkImageDigests() did not clear the condition when disabled")
+	}
+}
+
+func Test_ensureExternalChannel(t *testing.T) {
+	mch := full_mch.DeepCopy()
+	mch.Spec.Overrides = &operatorsv1.Overrides{ExternalChannel: &operatorsv1.ChannelReference{Name: "gitops-charts"}}
+	r, err := getTestReconciler(mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+
+	result, err := r.ensureExternalChannel(mch)
+	if result == nil || err != nil {
+		t.Fatalf("ensureExternalChannel() = %v, %v, want requeue for missing channel", result, err)
+	}
+
+	ch := channel.Channel(mch)
+	ch.SetName("gitops-charts")
+	if err := r.client.Create(context.TODO(), ch); err != nil {
+		t.Fatalf("Failed to create channel: %v", err)
+	}
+
+	result, err = r.ensureExternalChannel(mch)
+	if result != nil || err != nil {
+		t.Fatalf("ensureExternalChannel() = %v, %v, want nil, nil", result, err)
+	}
+	if c := GetHubCondition(mch.Status, operatorsv1.Progressing); c != nil && c.Reason == ExternalChannelMissingReason {
+		t.Errorf("ensureExternalChannel() did not clear the %s condition", ExternalChannelMissingReason)
+	}
+}
+
+func Test_ensureServiceDrift(t *testing.T) {
+	mch := full_mch.DeepCopy()
+	r, err := getTestReconciler(mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+
+	if _, err := r.ensureService(mch, helmrepo.Service(mch)); err != nil {
+		t.Fatalf("ensureService() error = %v", err)
+	}
+
+	found := &corev1.Service{}
+	key := types.NamespacedName{Name: helmrepo.HelmRepoName, Namespace: mch.Namespace}
+	if err := r.client.Get(context.TODO(), key, found); err != nil {
+		t.Fatalf("Failed to get Service: %v", err)
+	}
+	found.Spec.ClusterIP = "172.30.0.10"
+	found.Spec.Ports[0].Port = 8080
+	if err := r.client.Update(context.TODO(), found); err != nil {
+		t.Fatalf("Failed to update Service: %v", err)
+	}
+
+	if _, err := r.ensureService(mch, helmrepo.Service(mch)); err != nil {
+		t.Fatalf("ensureService() error = %v", err)
+	}
+	if err := r.client.Get(context.TODO(), key, found); err != nil {
+		t.Fatalf("Failed to get Service: %v", err)
+	}
+	if found.Spec.Ports[0].Port != int32(helmrepo.Port) {
+		t.Errorf("ensureService() port = %d, want %d", found.Spec.Ports[0].Port, helmrepo.Port)
+	}
+	if found.Spec.ClusterIP != "172.30.0.10" {
+		t.Errorf("ensureService() clusterIP = %s, want it preserved", found.Spec.ClusterIP)
+	}
+
+	// An up-to-date service is left alone
+	rv := found.ResourceVersion
+	if _, err := r.ensureService(mch, helmrepo.Service(mch)); err != nil {
+		t.Fatalf("ensureService() error = %v", err)
+	}
+	if err := r.client.Get(context.TODO(), key, found); err != nil {
+		t.Fatalf("Failed to get Service: %v", err)
+	}
+	if found.ResourceVersion != rv {
+		t.Errorf("ensureService() updated a service without drift")
+	}
+}
+
+func Test_ensureRoute(t *testing.T) {
+	mch := full_mch.DeepCopy()
+	mch.Spec.Ingress.Route = &operatorsv1.RouteSpec{Enabled: true, Host: "console.example.com"}
+	r, err := getTestReconciler(mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+
+	getHost := func() string {
+		found := &unstructured.Unstructured{}
+		found.SetGroupVersionKind(route.GroupVersion.WithKind("Route"))
+		err := r.client.Get(context.TODO(), types.NamespacedName{Name: route.RouteName, Namespace: mch.Namespace}, found)
+		if err != nil {
+			t.Fatalf("Could not find created route: %v", err)
+		}
+		host, _, _ := unstructured.NestedString(found.Object, "spec", "host")
+		return host
+	}
+
+	if _, err := r.ensureRoute(mch, route.Route(mch)); err != nil {
+		t.Fatalf("ensureRoute() error = %v", err)
+	}
+	if got := getHost(); got != "console.example.com" {
+		t.Errorf("ensureRoute() host = %v, want %v", got, "console.example.com")
+	}
+
+	mch.Spec.Ingress.Route.Host = "hub.example.com"
+	if _, err := r.ensureRoute(mch, route.Route(mch)); err != nil {
+		t.Fatalf("ensureRoute() error = %v", err)
+	}
+	if got := getHost(); got != "hub.example.com" {
+		t.Errorf("ensureRoute() host = %v, want %v", got, "hub.example.com")
+	}
+}
+
+func Test_ensureServiceMonitor(t *testing.T) {
+	mch := full_mch.DeepCopy()
+	mch.Spec.Overrides = &operatorsv1.Overrides{EnableServiceMonitors: true}
+	r, err := getTestReconciler(mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+
+	svc := foundation.WebhookService(mch)
+	svc.Spec.Ports = append(svc.Spec.Ports, corev1.ServicePort{Name: servicemonitor.MetricsPortName, Port: 8383})
+	sm := servicemonitor.ServiceMonitor(mch, svc)
+	if _, err := r.ensureServiceMonitor(mch, sm); err != nil {
+		t.Fatalf("ensureServiceMonitor() error = %v", err)
+	}
+
+	found := &unstructured.Unstructured{}
+	found.SetGroupVersionKind(servicemonitor.GroupVersion.WithKind("ServiceMonitor"))
+	key := types.NamespacedName{Name: foundation.WebhookName, Namespace: mch.Namespace}
+	if err := r.client.Get(context.TODO(), key, found); err != nil {
+		t.Fatalf("Failed to get ServiceMonitor: %v", err)
+	}
+
+	// Drift in the selector is corrected
+	_ = unstructured.SetNestedField(found.Object, map[string]interface{}{"app": "other"}, "spec", "selector", "matchLabels")
+	if err := r.client.Update(context.TODO(), found); err != nil {
+		t.Fatalf("Failed to update ServiceMonitor: %v", err)
+	}
+	if _, err := r.ensureServiceMonitor(mch, servicemonitor.ServiceMonitor(mch, svc)); err != nil {
+		t.Fatalf("ensureServiceMonitor() error = %v", err)
+	}
+	if err := r.client.Get(context.TODO(), key, found); err != nil {
+		t.Fatalf("Failed to get ServiceMonitor: %v", err)
+	}
+	if app, _, _ := unstructured.NestedString(found.Object, "spec", "selector", "matchLabels", "app"); app != foundation.WebhookName {
+		t.Errorf("ensureServiceMonitor() selector app = %v, want %v", app, foundation.WebhookName)
+	}
+}
+
 func Test_ensureSubscription(t *testing.T) {
 	os.Setenv("UNIT_TEST", "true")
 	defer os.Unsetenv("UNIT_TEST")
@@ -337,6 +1412,184 @@ func Test_ensureUnstructuredResource(t *testing.T) {
 	}
 }
 
+func Test_ensureResource(t *testing.T) {
+	mch := full_mch.DeepCopy()
+	r, err := getTestReconciler(mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+
+	configMap := func(value string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":      "test-config",
+				"namespace": mch.Namespace,
+			},
+			"data": map[string]interface{}{"key": value},
+		}}
+		utils.AddInstallerLabel(u, mch.Name, mch.Namespace)
+		return u
+	}
+	validateData := func(found, desired *unstructured.Unstructured) (*unstructured.Unstructured, bool) {
+		if reflect.DeepEqual(found.Object["data"], desired.Object["data"]) {
+			return nil, false
+		}
+		found.Object["data"] = desired.Object["data"]
+		return found, true
+	}
+
+	stale := configMap("created")
+	stale.SetUID("stale-uid")
+	stale.SetResourceVersion("42")
+
+	tests := []struct {
+		Name     string
+		Desired  *unstructured.Unstructured
+		Validate validateFunc
+		Want     string
+	}{
+		{
+			Name:     "Create with server-set metadata",
+			Desired:  stale,
+			Validate: validateData,
+			Want:     "created",
+		},
+		{
+			Name:     "No-op",
+			Desired:  configMap("created"),
+			Validate: validateData,
+			Want:     "created",
+		},
+		{
+			Name:     "Needs update",
+			Desired:  configMap("updated"),
+			Validate: validateData,
+			Want:     "updated",
+		},
+		{
+			Name:    "No validation",
+			Desired: configMap("ignored"),
+			Want:    "updated",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			result, err := r.ensureResource(mch, tt.Desired, tt.Validate)
+			if result != nil || err != nil {
+				t.Fatalf("ensureResource() = %v, %v, want nil, nil", result, err)
+			}
+
+			found := &corev1.ConfigMap{}
+			err = r.client.Get(context.TODO(), types.NamespacedName{Name: "test-config", Namespace: mch.Namespace}, found)
+			if err != nil {
+				t.Fatalf("Failed to get ConfigMap: %s", err)
+			}
+			if found.Data["key"] != tt.Want {
+				t.Errorf("ConfigMap data = %s, want %s", found.Data["key"], tt.Want)
+			}
+			if found.GetUID() == "stale-uid" || found.Labels["installer.name"] != mch.Name {
+				t.Errorf("ConfigMap metadata = %v, want installer labels and no stale UID", found.ObjectMeta)
+			}
+		})
+	}
+}
+
+func Test_apiReady(t *testing.T) {
+	r, err := getTestReconciler(full_mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+	fakeDiscovery := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{
+		Resources: []*metav1.APIResourceList{{GroupVersion: route.GroupVersion.String()}},
+	}}
+	r.discovery = fakeDiscovery
+
+	if result, err := r.apiReady(route.GroupVersion); result != nil || err != nil {
+		t.Errorf("apiReady() = %v, %v for a served API group, want nil, nil", result, err)
+	}
+	result, err := r.apiReady(servicemonitor.GroupVersion)
+	if err != nil || result == nil || result.RequeueAfter != 10*time.Second {
+		t.Errorf("apiReady() = %v, %v for an unserved API group, want a 10s requeue", result, err)
+	}
+	if r.discovery == nil {
+		t.Errorf("Discovery client was dropped for an unserved API group")
+	}
+
+	// A failed discovery request drops the client so it is rebuilt
+	fakeDiscovery.Resources = []*metav1.APIResourceList{{GroupVersion: "invalid/group/version"}}
+	result, err = r.apiReady(route.GroupVersion)
+	if err != nil || result == nil || result.RequeueAfter != 10*time.Second {
+		t.Errorf("apiReady() = %v, %v after a discovery failure, want a 10s requeue", result, err)
+	}
+	if r.discovery != nil {
+		t.Errorf("Discovery client was kept after a discovery failure")
+	}
+}
+
+func Test_copyPullSecret(t *testing.T) {
+	mch := full_mch.DeepCopy()
+	mch.Spec.ImagePullSecret = "pull-secret"
+	r, err := getTestReconciler(mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "pull-secret", Namespace: mch.Namespace},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{}}`)},
+	}
+	if err := r.client.Create(context.TODO(), source); err != nil {
+		t.Fatalf("Failed to create source secret: %s", err)
+	}
+
+	getCopy := func() *corev1.Secret {
+		copied := &corev1.Secret{}
+		err := r.client.Get(context.TODO(), types.NamespacedName{Name: "pull-secret", Namespace: utils.CertManagerNamespace}, copied)
+		if err != nil {
+			t.Fatalf("Failed to get copied secret: %s", err)
+		}
+		return copied
+	}
+
+	if _, err := r.copyPullSecret(mch, utils.CertManagerNamespace); err != nil {
+		t.Fatalf("Failed to copy pull secret: %s", err)
+	}
+	copied := getCopy()
+	if string(copied.Data[corev1.DockerConfigJsonKey]) != `{"auths":{}}` {
+		t.Errorf("Copied secret data = %s, want source data", copied.Data[corev1.DockerConfigJsonKey])
+	}
+
+	t.Run("Secrets match", func(t *testing.T) {
+		if _, err := r.copyPullSecret(mch, utils.CertManagerNamespace); err != nil {
+			t.Fatalf("Failed to copy pull secret: %s", err)
+		}
+		if rv := getCopy().ResourceVersion; rv != copied.ResourceVersion {
+			t.Errorf("Copied secret was updated when secrets already match")
+		}
+	})
+
+	t.Run("Source rotated", func(t *testing.T) {
+		source.Data[corev1.DockerConfigJsonKey] = []byte(`{"auths":{"quay.io":{}}}`)
+		if err := r.client.Update(context.TODO(), source); err != nil {
+			t.Fatalf("Failed to update source secret: %s", err)
+		}
+		if _, err := r.copyPullSecret(mch, utils.CertManagerNamespace); err != nil {
+			t.Fatalf("Failed to copy pull secret: %s", err)
+		}
+		updated := getCopy()
+		if string(updated.Data[corev1.DockerConfigJsonKey]) != `{"auths":{"quay.io":{}}}` {
+			t.Errorf("Copied secret data = %s, want rotated data", updated.Data[corev1.DockerConfigJsonKey])
+		}
+		if updated.Labels["installer.name"] != mch.Name || updated.Labels["installer.namespace"] != mch.Namespace {
+			t.Errorf("Copied secret is missing installer labels: %v", updated.Labels)
+		}
+	})
+}
+
 func Test_OverrideImagesFromConfigmap(t *testing.T) {
 	os.Setenv("MANIFESTS_PATH", "../../../image-manifests")
 	defer os.Unsetenv("MANIFESTS_PATH")
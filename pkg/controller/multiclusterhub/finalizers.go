@@ -166,7 +166,7 @@ func (r *ReconcileMultiClusterHub) cleanupPullSecret(reqLogger logr.Logger, m *o
 		return err
 	}
 
-	reqLogger.Info(fmt.Sprintf("%s secret finalized", utils.CertManagerNS(m)))
+	reqLogger.Info(fmt.Sprintf("%s secret finalized", utils.CertManagerNamespace))
 	return nil
 }
 
@@ -300,7 +300,7 @@ func (r *ReconcileMultiClusterHub) cleanupAppSubscriptions(reqLogger logr.Logger
 		reqLogger.Info("Terminating App Subscriptions")
 		for i, appsub := range appSubList.Items {
 			err = r.client.Delete(context.TODO(), &appSubList.Items[i])
-			if err != nil {
+			if err != nil && !errors.IsNotFound(err) {
 				reqLogger.Error(err, fmt.Sprintf("Error terminating sub: %s", appsub.GetName()))
 				return err
 			}
@@ -392,14 +392,39 @@ func (r *ReconcileMultiClusterHub) cleanupFoundation(reqLogger logr.Logger, m *o
 		return err
 	}
 
+	reqLogger.Info("All foundation artefacts have been terminated")
+
+	return nil
+}
+
+func (r *ReconcileMultiClusterHub) cleanupChannels(reqLogger logr.Logger, m *operatorsv1.MultiClusterHub) error {
+	if channel.IsExternal(m) {
+		reqLogger.Info("Subscriptions use an external channel. Continuing.")
+		return nil
+	}
+
 	reqLogger.Info("Deleting MultiClusterHub channel")
-	err = r.client.Delete(context.TODO(), channel.Channel(m))
+	err := r.client.Delete(context.TODO(), channel.Channel(m))
 	if err != nil && !errors.IsNotFound(err) {
 		reqLogger.Error(err, "Error deleting MultiClusterHub channel")
 		return err
 	}
 
-	reqLogger.Info("All foundation artefacts have been terminated")
-
+	reqLogger.Info("Channels finalized")
 	return nil
 }
+
+// apiServed returns false if the API group version is no longer served, so finalizers do not block on
+// resources that can't exist. It returns an error if discovery fails
+func (r *ReconcileMultiClusterHub) apiServed(gv schema.GroupVersion) (bool, error) {
+	// Skip discovery on unit test
+	if utils.IsUnitTest() {
+		return true, nil
+	}
+	c, err := r.discoveryClient()
+	if err != nil {
+		return false, err
+	}
+	// A discovery failure is returned so finalizers retry instead of skipping cleanup
+	return r.groupVersionServed(c, gv)
+}
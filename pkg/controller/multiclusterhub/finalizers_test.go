@@ -21,7 +21,10 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
 	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 )
 
@@ -649,3 +652,84 @@ func Test_cleanupFoundation(t *testing.T) {
 		})
 	}
 }
+
+func Test_cleanupChannels(t *testing.T) {
+	reqLogger := log.WithValues("Request.Namespace", mch_namespace, "Request.Name", mch_name)
+
+	t.Run("Operator channel", func(t *testing.T) {
+		r, err := getTestReconciler(full_mch)
+		if err != nil {
+			t.Fatalf("Failed to create test reconciler: %s", err)
+		}
+		if result, err := r.ensureChannel(full_mch, channel.Channel(full_mch)); result != nil {
+			t.Fatalf("Failed to ensure channel: %s", err)
+		}
+
+		if err := r.cleanupChannels(reqLogger, full_mch); err != nil {
+			t.Fatalf("Failed to cleanup channels: %s", err)
+		}
+		found := &unstructured.Unstructured{}
+		found.SetGroupVersionKind(channel.Channel(full_mch).GroupVersionKind())
+		err = r.client.Get(context.TODO(), types.NamespacedName{Name: channel.ChannelName, Namespace: full_mch.Namespace}, found)
+		if !errors.IsNotFound(err) {
+			t.Errorf("cleanupChannels() error = %v, wanted isNotFound error", err)
+		}
+
+		// Cleanup is idempotent once the channel is gone
+		if err := r.cleanupChannels(reqLogger, full_mch); err != nil {
+			t.Errorf("cleanupChannels() error = %v on second run", err)
+		}
+	})
+
+	t.Run("External channel", func(t *testing.T) {
+		mch := full_mch.DeepCopy()
+		mch.Spec.Overrides = &operatorsv1.Overrides{ExternalChannel: &operatorsv1.ChannelReference{Name: channel.ChannelName}}
+		r, err := getTestReconciler(mch)
+		if err != nil {
+			t.Fatalf("Failed to create test reconciler: %s", err)
+		}
+		if err := r.client.Create(context.TODO(), channel.Channel(mch)); err != nil {
+			t.Fatalf("Failed to create channel: %s", err)
+		}
+
+		if err := r.cleanupChannels(reqLogger, mch); err != nil {
+			t.Fatalf("Failed to cleanup channels: %s", err)
+		}
+		found := &unstructured.Unstructured{}
+		found.SetGroupVersionKind(channel.Channel(mch).GroupVersionKind())
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: channel.ChannelName, Namespace: mch.Namespace}, found); err != nil {
+			t.Errorf("cleanupChannels() deleted an external channel: %v", err)
+		}
+	})
+}
+
+func Test_apiServed(t *testing.T) {
+	mch := full_mch.DeepCopy()
+	r, err := getTestReconciler(mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+	apps := schema.GroupVersion{Group: "apps.open-cluster-management.io", Version: "v1"}
+	hive := schema.GroupVersion{Group: "hive.openshift.io", Version: "v1"}
+	fakeDiscovery := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{
+		Resources: []*metav1.APIResourceList{{GroupVersion: apps.String()}},
+	}}
+	r.discovery = fakeDiscovery
+
+	if served, err := r.apiServed(apps); !served || err != nil {
+		t.Errorf("apiServed() = %v, %v for a served API group, want true, nil", served, err)
+	}
+	if served, err := r.apiServed(hive); served || err != nil {
+		t.Errorf("apiServed() = %v, %v for an unserved API group, want false, nil", served, err)
+	}
+
+	// A failed discovery request is an error, so finalization retries instead of skipping cleanup
+	fakeDiscovery.Resources = []*metav1.APIResourceList{{GroupVersion: "invalid/group/version"}}
+	if _, err := r.apiServed(apps); err == nil {
+		t.Errorf("apiServed() did not return an error after a discovery failure")
+	}
+	r.discovery = fakeDiscovery
+	if err := r.finalizeHub(log, mch); err == nil {
+		t.Errorf("finalizeHub() did not return an error after a discovery failure")
+	}
+}
@@ -17,6 +17,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -34,12 +36,17 @@ import (
 	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/channel"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/deploying"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/events"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/foundation"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/helmrepo"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/imageoverrides"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/manifest"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/predicate"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/readiness"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/registry"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/rendering"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/route"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/servicemonitor"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/subscription"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/utils"
 	"github.com/open-cluster-management/multicloudhub-operator/version"
@@ -52,6 +59,12 @@ const hubFinalizer = "finalizer.operator.open-cluster-management.io"
 var log = logf.Log.WithName("controller_multiclusterhub")
 var resyncPeriod = time.Second * 20
 
+// digestGracePeriod is how long pods of a deployment may run different image digests before it is reported
+var digestGracePeriod = 10 * time.Minute
+
+// imageArchitectureTimeout bounds all registry queries made by one image architecture check
+var imageArchitectureTimeout = 10 * time.Second
+
 /**
 * USER ACTION REQUIRED: This is a scaffold file intended for the user to modify with their own Controller
 * business logic.  Delete these comments after modifying this file.*
@@ -65,7 +78,12 @@ func Add(mgr manager.Manager) error {
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileMultiClusterHub{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+	return &ReconcileMultiClusterHub{
+		client:    mgr.GetClient(),
+		apiReader: mgr.GetAPIReader(),
+		scheme:    mgr.GetScheme(),
+		recorder:  events.NewThrottledRecorder(mgr.GetEventRecorderFor("multiclusterhub-operator"), events.DefaultThrottleWindow),
+	}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -197,6 +215,44 @@ type ReconcileMultiClusterHub struct {
 	client    client.Client
 	CacheSpec CacheSpec
 	scheme    *runtime.Scheme
+	// apiReader reads directly from the apiserver, for kinds that should not be cached cluster-wide
+	apiReader client.Reader
+	// recorder emits events on the MultiClusterHub, throttling repeated identical events
+	recorder record.EventRecorder
+	// discovery is created on first use and dropped after a failed request so it is rebuilt
+	discovery discovery.DiscoveryInterface
+	// hubs holds the in-memory reconcile state of each hub, dropped once the hub is deleted
+	hubs map[types.NamespacedName]*hubState
+}
+
+// hubState is the reconcile state kept in memory for one hub
+type hubState struct {
+	// ownershipConflicts describes managed resources claimed by another controller, keyed by kind and name
+	ownershipConflicts map[string]string
+	// digestsDivergedSince records when the pods of each deployment were first seen running different image digests
+	digestsDivergedSince map[string]time.Time
+	// reconcileErrors counts consecutive reconciles that returned an error
+	reconcileErrors int
+	// backoff is set once the reconcile error budget is exhausted
+	backoff *reconcileBackoff
+	// skippedComponents describes the API groups missing for each component subscription that is not installed
+	skippedComponents map[string]string
+	// blockedScaleDowns describes the replicas and PodDisruptionBudget holding back each deployment scale down
+	blockedScaleDowns map[string]string
+	// deferredUpdates records the deployments whose image update was deferred when they were last ensured
+	deferredUpdates map[string]bool
+}
+
+// state returns the in-memory reconcile state of the hub, creating it on first use
+func (r *ReconcileMultiClusterHub) state(m *operatorsv1.MultiClusterHub) *hubState {
+	key := types.NamespacedName{Name: m.GetName(), Namespace: m.GetNamespace()}
+	if r.hubs == nil {
+		r.hubs = map[types.NamespacedName]*hubState{}
+	}
+	if r.hubs[key] == nil {
+		r.hubs[key] = &hubState{}
+	}
+	return r.hubs[key]
 }
 
 // Reconcile reads that state of the cluster for a MultiClusterHub object and makes changes based on the state read
@@ -217,6 +273,7 @@ func (r *ReconcileMultiClusterHub) Reconcile(request reconcile.Request) (retQueu
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
 			reqLogger.Info("MultiClusterHub resource not found. Ignoring since object must be deleted")
+			delete(r.hubs, request.NamespacedName)
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -242,6 +299,7 @@ func (r *ReconcileMultiClusterHub) Reconcile(request reconcile.Request) (retQueu
 	originalStatus := multiClusterHub.Status.DeepCopy()
 	defer func() {
 		statusQueue, statusError := r.syncHubStatus(multiClusterHub, originalStatus, allDeploys, allHRs, allCRs)
+		readiness.SetPhase(multiClusterHub.Status.Phase)
 		if statusError != nil {
 			log.Error(retError, "Error updating status")
 		}
@@ -252,6 +310,9 @@ func (r *ReconcileMultiClusterHub) Reconcile(request reconcile.Request) (retQueu
 			retError = statusError
 		}
 	}()
+	defer func() {
+		r.recordReconcileResult(multiClusterHub, retError)
+	}()
 
 	// Check if the multiClusterHub instance is marked to be deleted, which is
 	// indicated by the deletion timestamp being set.
@@ -283,6 +344,19 @@ func (r *ReconcileMultiClusterHub) Reconcile(request reconcile.Request) (retQueu
 		return reconcile.Result{}, nil
 	}
 
+	if r.backedOff(multiClusterHub) {
+		reqLogger.Info("MultiClusterHub reconcile error budget is exhausted. Waiting for a spec change or resume request.")
+		return reconcile.Result{}, nil
+	}
+
+	if message := operatorNamespaceMismatch(multiClusterHub); message != "" {
+		reqLogger.Info(message)
+		condition := NewHubCondition(operatorsv1.ConfigError, metav1.ConditionTrue, NamespaceMismatchReason, message)
+		SetHubCondition(&multiClusterHub.Status, *condition)
+		return reconcile.Result{RequeueAfter: resyncPeriod}, nil
+	}
+	removeConfigError(multiClusterHub, NamespaceMismatchReason)
+
 	// Add finalizer for this CR
 	if !contains(multiClusterHub.GetFinalizers(), hubFinalizer) {
 		if err := r.addFinalizer(reqLogger, multiClusterHub); err != nil {
@@ -296,6 +370,15 @@ func (r *ReconcileMultiClusterHub) Reconcile(request reconcile.Request) (retQueu
 		return *result, err
 	}
 
+	if suffix := utils.GetImageTagSuffix(multiClusterHub); suffix != "" && !utils.IsValidImageTagSuffix(suffix) {
+		message := fmt.Sprintf("Image tag suffix %q is not a valid image tag fragment", suffix)
+		reqLogger.Info(message)
+		condition := NewHubCondition(operatorsv1.ConfigError, metav1.ConditionTrue, InvalidImageTagSuffixReason, message)
+		SetHubCondition(&multiClusterHub.Status, *condition)
+		return reconcile.Result{RequeueAfter: resyncPeriod}, nil
+	}
+	removeConfigError(multiClusterHub, InvalidImageTagSuffixReason)
+
 	// Read image overrides
 	// First, attempt to read image overrides from environmental variables
 	imageOverrides := imageoverrides.GetImageOverrides()
@@ -307,6 +390,8 @@ func (r *ReconcileMultiClusterHub) Reconcile(request reconcile.Request) (retQueu
 			reqLogger.Error(err, "Could not get map of image overrides")
 			return reconcile.Result{}, err
 		}
+	} else if suffix := utils.GetImageTagSuffix(multiClusterHub); suffix != "" {
+		imageOverrides = utils.AppendImageTagSuffix(imageOverrides, suffix)
 	}
 
 	if imageRepo := utils.GetImageRepository(multiClusterHub); imageRepo != "" {
@@ -322,6 +407,7 @@ func (r *ReconcileMultiClusterHub) Reconcile(request reconcile.Request) (retQueu
 			return reconcile.Result{}, err
 		}
 	}
+
 	r.CacheSpec.ImageOverrides = imageOverrides
 	r.CacheSpec.ManifestVersion = version.Version
 	r.CacheSpec.ImageOverrideType = manifest.GetImageOverrideType(multiClusterHub)
@@ -329,12 +415,27 @@ func (r *ReconcileMultiClusterHub) Reconcile(request reconcile.Request) (retQueu
 	r.CacheSpec.ImageSuffix = utils.GetImageSuffix(multiClusterHub)
 	r.CacheSpec.ImageOverridesCM = utils.GetImageOverridesConfigmap(multiClusterHub)
 
+	r.CacheSpec.Prerequisites, err = manifest.GetPrerequisites()
+	if err != nil {
+		reqLogger.Error(err, "Could not read component prerequisites")
+		return reconcile.Result{}, err
+	}
+
+	r.CacheSpec.ClusterScaledReplicas, err = r.clusterScaledReplicas(multiClusterHub)
+	if err != nil {
+		reqLogger.Error(err, "Error counting managed clusters")
+		return reconcile.Result{}, err
+	}
+
 	err = r.maintainImageManifestConfigmap(multiClusterHub)
 	if err != nil {
 		reqLogger.Error(err, "Error storing image manifests in configmap")
 		return reconcile.Result{}, err
 	}
 
+	// Gather operator state for support cases once the configuration is resolved
+	r.ensureSupportBundle(multiClusterHub)
+
 	CustomUpgradeRequired, err := r.CustomSelfMgmtHubUpgradeRequired(multiClusterHub)
 	if err != nil {
 		reqLogger.Error(err, "Error determining if upgrade specific logic is required")
@@ -363,6 +464,11 @@ func (r *ReconcileMultiClusterHub) Reconcile(request reconcile.Request) (retQueu
 		return reconcile.Result{}, nil
 	}
 
+	result, err = r.ensureRequiredOperators(multiClusterHub)
+	if result != nil {
+		return *result, err
+	}
+
 	result, err = r.ensureSubscriptionOperatorIsRunning(multiClusterHub, allDeploys)
 	if result != nil {
 		return *result, err
@@ -374,6 +480,52 @@ func (r *ReconcileMultiClusterHub) Reconcile(request reconcile.Request) (retQueu
 		return reconcile.Result{}, err
 	}
 
+	result, err = r.ensurePodSecurityLabels(multiClusterHub)
+	if result != nil {
+		return *result, err
+	}
+
+	result, err = r.ensureNodePortsAvailable(multiClusterHub, []*corev1.Service{
+		helmrepo.Service(multiClusterHub),
+		foundation.WebhookService(multiClusterHub),
+		foundation.OCMProxyServerService(multiClusterHub),
+	})
+	if result != nil {
+		return *result, err
+	}
+
+	// Image updates are not deferred while the update windows are invalid
+	if err := utils.ValidateUpdateWindows(multiClusterHub); err != nil {
+		message := fmt.Sprintf("Image updates are not restricted because an update window is invalid: %s", err.Error())
+		reqLogger.Info(message)
+		condition := NewHubCondition(operatorsv1.ConfigError, metav1.ConditionTrue, InvalidUpdateWindowReason, message)
+		SetHubCondition(&multiClusterHub.Status, *condition)
+	} else {
+		removeConfigError(multiClusterHub, InvalidUpdateWindowReason)
+	}
+
+	// Deferred image updates roll out once the update window opens
+	if utils.InUpdateWindow(multiClusterHub, time.Now()) {
+		RemoveHubCondition(&multiClusterHub.Status, operatorsv1.PendingUpdate)
+		multiClusterHub.Status.NextUpdateWindow = nil
+	}
+
+	r.checkResourceQuota(multiClusterHub, []*appsv1.Deployment{
+		helmrepo.Deployment(multiClusterHub, r.CacheSpec.ImageOverrides),
+		foundation.WebhookDeployment(multiClusterHub, r.CacheSpec.ImageOverrides),
+		foundation.OCMProxyServerDeployment(multiClusterHub, r.CacheSpec.ImageOverrides),
+		foundation.OCMControllerDeployment(multiClusterHub, r.CacheSpec.ImageOverrides),
+	})
+
+	r.checkImageArchitectures(multiClusterHub, registry.Architectures)
+
+	r.checkImageDigests(multiClusterHub, []*appsv1.Deployment{
+		helmrepo.Deployment(multiClusterHub, r.CacheSpec.ImageOverrides),
+		foundation.WebhookDeployment(multiClusterHub, r.CacheSpec.ImageOverrides),
+		foundation.OCMProxyServerDeployment(multiClusterHub, r.CacheSpec.ImageOverrides),
+		foundation.OCMControllerDeployment(multiClusterHub, r.CacheSpec.ImageOverrides),
+	}, time.Now())
+
 	result, err = r.ensureDeployment(multiClusterHub, helmrepo.Deployment(multiClusterHub, r.CacheSpec.ImageOverrides))
 	if result != nil {
 		return *result, err
@@ -384,7 +536,25 @@ func (r *ReconcileMultiClusterHub) Reconcile(request reconcile.Request) (retQueu
 		return *result, err
 	}
 
-	result, err = r.ensureChannel(multiClusterHub, channel.Channel(multiClusterHub))
+	// Ensure the chart cache claim after its deployment, so a claim waiting for its first consumer can bind
+	if utils.GetPersistence(multiClusterHub) != nil {
+		result, err = r.ensurePVC(multiClusterHub, helmrepo.PersistentVolumeClaim(multiClusterHub))
+		if result != nil {
+			return *result, err
+		}
+	}
+
+	// Record the charts served once the helm repo is available
+	repoStatus := multiClusterHub.Status.Components[helmrepo.HelmRepoName]
+	if !utils.IsUnitTest() && repoStatus.Type == "Available" && repoStatus.Status == metav1.ConditionTrue {
+		r.recordChartIndex(multiClusterHub, helmrepo.IndexURL(multiClusterHub))
+	}
+
+	if channel.IsExternal(multiClusterHub) {
+		result, err = r.ensureExternalChannel(multiClusterHub)
+	} else {
+		result, err = r.ensureChannel(multiClusterHub, channel.Channel(multiClusterHub))
+	}
 	if result != nil {
 		return *result, err
 	}
@@ -457,6 +627,11 @@ func (r *ReconcileMultiClusterHub) Reconcile(request reconcile.Request) (retQueu
 		}
 	}
 
+	result, err = r.ensureWebhookCABundle(multiClusterHub)
+	if result != nil {
+		return *result, err
+	}
+
 	result, err = r.ensureDeployment(multiClusterHub, foundation.WebhookDeployment(multiClusterHub, r.CacheSpec.ImageOverrides))
 	if result != nil {
 		return *result, err
@@ -478,6 +653,21 @@ func (r *ReconcileMultiClusterHub) Reconcile(request reconcile.Request) (retQueu
 	if result != nil {
 		return *result, err
 	}
+
+	if route.Enabled(multiClusterHub) {
+		// Skip wait for API to be ready on unit test
+		if !utils.IsUnitTest() {
+			result, err = r.apiReady(route.GroupVersion)
+			if result != nil {
+				return *result, err
+			}
+		}
+		result, err = r.ensureRoute(multiClusterHub, route.Route(multiClusterHub))
+		if result != nil {
+			return *result, err
+		}
+	}
+
 	result, err = r.ensureSubscription(multiClusterHub, subscription.ApplicationUI(multiClusterHub, r.CacheSpec.ImageOverrides))
 	if result != nil {
 		return *result, err
@@ -538,6 +728,7 @@ func (r *ReconcileMultiClusterHub) Reconcile(request reconcile.Request) (retQueu
 	if result != nil {
 		return *result, err
 	}
+	r.clearPendingUpdate(multiClusterHub)
 
 	result, err = r.ensureUnstructuredResource(multiClusterHub, foundation.ClusterManager(multiClusterHub, r.CacheSpec.ImageOverrides))
 	if result != nil {
@@ -564,6 +755,51 @@ func (r *ReconcileMultiClusterHub) Reconcile(request reconcile.Request) (retQueu
 		}
 	}
 
+	componentServices := []*corev1.Service{
+		helmrepo.Service(multiClusterHub),
+		foundation.WebhookService(multiClusterHub),
+		foundation.OCMProxyServerService(multiClusterHub),
+	}
+
+	if servicemonitor.MetricsServicesEnabled(multiClusterHub) {
+		for _, svc := range componentServices {
+			// Components without a metrics port are not exposed
+			ms := servicemonitor.MetricsService(multiClusterHub, svc)
+			if ms == nil {
+				continue
+			}
+			result, err = r.ensureService(multiClusterHub, ms)
+			if result != nil {
+				return *result, err
+			}
+		}
+	}
+
+	if servicemonitor.Enabled(multiClusterHub) {
+		// Skip wait for API to be ready on unit test
+		if !utils.IsUnitTest() {
+			result, err = r.apiReady(servicemonitor.GroupVersion)
+			if result != nil {
+				return *result, err
+			}
+		}
+		for _, svc := range componentServices {
+			sm := servicemonitor.ServiceMonitor(multiClusterHub, svc)
+			if sm == nil {
+				continue
+			}
+			if servicemonitor.MetricsServicesEnabled(multiClusterHub) {
+				// Scrape the metrics service, keeping the ServiceMonitor named after the component
+				sm = servicemonitor.ServiceMonitor(multiClusterHub, servicemonitor.MetricsService(multiClusterHub, svc))
+				sm.SetName(svc.Name)
+			}
+			result, err = r.ensureServiceMonitor(multiClusterHub, sm)
+			if result != nil {
+				return *result, err
+			}
+		}
+	}
+
 	return retQueue, retError
 }
 
@@ -617,15 +853,34 @@ func (r *ReconcileMultiClusterHub) finalizeHub(reqLogger logr.Logger, m *operato
 	if _, err := r.ensureHubIsExported(m); err != nil {
 		return err
 	}
-	if err := r.cleanupAppSubscriptions(reqLogger, m); err != nil {
+	appsServed, err := r.apiServed(schema.GroupVersion{Group: "apps.open-cluster-management.io", Version: "v1"})
+	if err != nil {
 		return err
 	}
+	if appsServed {
+		if err := r.cleanupAppSubscriptions(reqLogger, m); err != nil {
+			return err
+		}
+	} else {
+		reqLogger.Info("Application API is not served. Skipping subscription and channel cleanup.")
+	}
 	if err := r.cleanupFoundation(reqLogger, m); err != nil {
 		return err
 	}
-	if err := r.cleanupHiveConfigs(reqLogger, m); err != nil {
+	if appsServed {
+		if err := r.cleanupChannels(reqLogger, m); err != nil {
+			return err
+		}
+	}
+	hiveServed, err := r.apiServed(schema.GroupVersion{Group: "hive.openshift.io", Version: "v1"})
+	if err != nil {
 		return err
 	}
+	if hiveServed {
+		if err := r.cleanupHiveConfigs(reqLogger, m); err != nil {
+			return err
+		}
+	}
 	if err := r.cleanupAPIServices(reqLogger, m); err != nil {
 		return err
 	}
@@ -19,6 +19,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -245,5 +246,5 @@ func getTestReconciler(m *operatorsv1.MultiClusterHub) (*ReconcileMultiClusterHu
 	cl := fake.NewFakeClient(objs...)
 
 	// Create a ReconcileMultiClusterHub object with the scheme and fake client.
-	return &ReconcileMultiClusterHub{client: cl, scheme: s}, nil
+	return &ReconcileMultiClusterHub{client: cl, apiReader: cl, scheme: s, recorder: record.NewFakeRecorder(100)}, nil
 }
@@ -0,0 +1,73 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package multiclusterhub
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ownershipConflict describes how a managed resource is claimed by something other than the hub, or returns
+// an empty string when the hub still owns it
+func ownershipConflict(m *operatorsv1.MultiClusterHub, obj metav1.Object) string {
+	if ref := metav1.GetControllerOf(obj); ref != nil && ref.UID != m.GetUID() {
+		return fmt.Sprintf("controlled by %s %s", ref.Kind, ref.Name)
+	}
+	labels := obj.GetLabels()
+	name, nameExists := labels["installer.name"]
+	namespace, namespaceExists := labels["installer.namespace"]
+	if (nameExists && name != m.GetName()) || (namespaceExists && namespace != m.GetNamespace()) {
+		return fmt.Sprintf("labeled for installer %s/%s", namespace, name)
+	}
+	return ""
+}
+
+// checkOwnership records whether a managed resource found in the cluster is claimed by another controller
+// and returns true if so. Callers back off updating a conflicting resource rather than fighting over it.
+func (r *ReconcileMultiClusterHub) checkOwnership(m *operatorsv1.MultiClusterHub, kind string, obj metav1.Object) bool {
+	state := r.state(m)
+	key := fmt.Sprintf("%s %s", kind, obj.GetName())
+	conflict := ownershipConflict(m, obj)
+	if conflict == "" {
+		delete(state.ownershipConflicts, key)
+		r.updateOwnershipCondition(m)
+		return false
+	}
+
+	if state.ownershipConflicts == nil {
+		state.ownershipConflicts = map[string]string{}
+	}
+	if _, ok := state.ownershipConflicts[key]; !ok {
+		log.Info("Not updating managed resource claimed by another controller", "Resource", key, "Conflict", conflict)
+		r.recorder.Eventf(m, corev1.EventTypeWarning, OwnershipConflictReason, "%s is %s; not updating it", key, conflict)
+	}
+	state.ownershipConflicts[key] = conflict
+	r.updateOwnershipCondition(m)
+	return true
+}
+
+// updateOwnershipCondition sets the OwnershipConflict condition to name every known conflict, removing it
+// once none remain
+func (r *ReconcileMultiClusterHub) updateOwnershipCondition(m *operatorsv1.MultiClusterHub) {
+	state := r.state(m)
+	if len(state.ownershipConflicts) == 0 {
+		RemoveHubCondition(&m.Status, operatorsv1.OwnershipConflict)
+		return
+	}
+
+	conflicts := []string{}
+	for key, conflict := range state.ownershipConflicts {
+		conflicts = append(conflicts, fmt.Sprintf("%s is %s", key, conflict))
+	}
+	sort.Strings(conflicts)
+	message := fmt.Sprintf("Managed resources are claimed by another controller and are not updated: %s", strings.Join(conflicts, "; "))
+
+	condition := NewHubCondition(operatorsv1.OwnershipConflict, metav1.ConditionTrue, OwnershipConflictReason, message)
+	ReplaceHubCondition(&m.Status, *condition)
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package multiclusterhub
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/foundation"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func Test_ownershipConflict(t *testing.T) {
+	mch := full_mch.DeepCopy()
+	mch.SetUID("hub-uid")
+	isController := true
+
+	tests := []struct {
+		name   string
+		obj    metav1.ObjectMeta
+		expect bool
+	}{
+		{
+			name: "Owned by hub",
+			obj: metav1.ObjectMeta{
+				OwnerReferences: []metav1.OwnerReference{{Kind: "MultiClusterHub", Name: mch.Name, UID: mch.UID, Controller: &isController}},
+				Labels:          map[string]string{"installer.name": mch.Name, "installer.namespace": mch.Namespace},
+			},
+			expect: false,
+		},
+		{
+			name:   "No ownership",
+			obj:    metav1.ObjectMeta{},
+			expect: false,
+		},
+		{
+			name: "Other controller",
+			obj: metav1.ObjectMeta{
+				OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "other-operator", UID: "other-uid", Controller: &isController}},
+			},
+			expect: true,
+		},
+		{
+			name: "Non-controller owner",
+			obj: metav1.ObjectMeta{
+				OwnerReferences: []metav1.OwnerReference{{Kind: "ConfigMap", Name: "other", UID: "other-uid"}},
+			},
+			expect: false,
+		},
+		{
+			name: "Other installer",
+			obj: metav1.ObjectMeta{
+				Labels: map[string]string{"installer.name": "other-hub", "installer.namespace": mch.Namespace},
+			},
+			expect: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ownershipConflict(mch, &tt.obj); (got != "") != tt.expect {
+				t.Errorf("ownershipConflict() = %q, want conflict %t", got, tt.expect)
+			}
+		})
+	}
+}
+
+func Test_ensureDeploymentOwnershipConflict(t *testing.T) {
+	mch := full_mch.DeepCopy()
+	r, err := getTestReconciler(mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+
+	if _, err := r.ensureDeployment(mch, foundation.WebhookDeployment(mch, map[string]string{})); err != nil {
+		t.Fatalf("ensureDeployment() error = %v", err)
+	}
+
+	getDeployment := func() *appsv1.Deployment {
+		found := &appsv1.Deployment{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: foundation.WebhookName, Namespace: mch.Namespace}, found); err != nil {
+			t.Fatalf("Failed to get deployment: %v", err)
+		}
+		return found
+	}
+
+	// Another installer adopts the deployment and changes it
+	found := getDeployment()
+	found.Labels["installer.name"] = "other-hub"
+	found.Spec.Template.Spec.Containers[0].Args = []string{"--other"}
+	if err := r.client.Update(context.TODO(), found); err != nil {
+		t.Fatalf("Failed to update deployment: %v", err)
+	}
+
+	if result, err := r.ensureDeployment(mch, foundation.WebhookDeployment(mch, map[string]string{})); result != nil || err != nil {
+		t.Fatalf("ensureDeployment() = %v, %v, want nil, nil", result, err)
+	}
+	if got := getDeployment().Spec.Template.Spec.Containers[0].Args; len(got) != 1 || got[0] != "--other" {
+		t.Errorf("ensureDeployment() updated a deployment claimed by another installer")
+	}
+	c := GetHubCondition(mch.Status, operatorsv1.OwnershipConflict)
+	if c == nil || !strings.Contains(c.Message, "Deployment "+foundation.WebhookName) {
+		t.Fatalf("ensureDeployment() did not report the ownership conflict, condition = %v", c)
+	}
+
+	corrections := testutil.ToFloat64(metrics.DriftCorrections.WithLabelValues("Deployment", foundation.WebhookName))
+
+	// The hub reclaims the deployment once the conflict is resolved
+	found = getDeployment()
+	found.Labels["installer.name"] = mch.Name
+	if err := r.client.Update(context.TODO(), found); err != nil {
+		t.Fatalf("Failed to update deployment: %v", err)
+	}
+	if _, err := r.ensureDeployment(mch, foundation.WebhookDeployment(mch, map[string]string{})); err != nil {
+		t.Fatalf("ensureDeployment() error = %v", err)
+	}
+	if c := GetHubCondition(mch.Status, operatorsv1.OwnershipConflict); c != nil {
+		t.Errorf("ensureDeployment() did not clear the ownership conflict, condition = %v", c)
+	}
+	if got := getDeployment().Spec.Template.Spec.Containers[0].Args; len(got) == 1 && got[0] == "--other" {
+		t.Errorf("ensureDeployment() did not restore the deployment after the conflict was resolved")
+	}
+	if got := testutil.ToFloat64(metrics.DriftCorrections.WithLabelValues("Deployment", foundation.WebhookName)); got != corrections+1 {
+		t.Errorf("drift_corrections_total = %v, want %v", got, corrections+1)
+	}
+}
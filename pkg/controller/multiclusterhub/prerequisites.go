@@ -0,0 +1,68 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package multiclusterhub
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// prerequisitesMissing checks that the API groups a component subscription requires are served and returns
+// true if any are not. Callers skip installing such a component and continue with the rest of the hub.
+func (r *ReconcileMultiClusterHub) prerequisitesMissing(m *operatorsv1.MultiClusterHub, component string) bool {
+	var missing []string
+	for _, gv := range r.CacheSpec.Prerequisites[component] {
+		result, err := r.apiReady(gv)
+		if err != nil {
+			log.Error(err, "Failed to check prerequisite API group", "Component", component, "API group", gv)
+		}
+		if result != nil {
+			missing = append(missing, gv.String())
+		}
+	}
+
+	state := r.state(m)
+	if len(missing) == 0 {
+		delete(state.skippedComponents, component)
+		r.updatePrerequisitesCondition(m)
+		return false
+	}
+
+	if state.skippedComponents == nil {
+		state.skippedComponents = map[string]string{}
+	}
+	description := strings.Join(missing, ", ")
+	if state.skippedComponents[component] != description {
+		log.Info("Skipping component with missing prerequisite API groups", "Component", component, "Missing", description)
+		r.recorder.Eventf(m, corev1.EventTypeWarning, PrerequisitesMissingReason, "Not installing %s until these API groups are served: %s", component, description)
+	}
+	state.skippedComponents[component] = description
+	r.updatePrerequisitesCondition(m)
+	return true
+}
+
+// updatePrerequisitesCondition sets the PrerequisitesMissing condition to name every skipped component,
+// removing it once none remain
+func (r *ReconcileMultiClusterHub) updatePrerequisitesCondition(m *operatorsv1.MultiClusterHub) {
+	state := r.state(m)
+	if len(state.skippedComponents) == 0 {
+		RemoveHubCondition(&m.Status, operatorsv1.PrerequisitesMissing)
+		return
+	}
+
+	skipped := []string{}
+	for component, missing := range state.skippedComponents {
+		skipped = append(skipped, fmt.Sprintf("%s (%s)", component, missing))
+	}
+	sort.Strings(skipped)
+	message := fmt.Sprintf("Components are not installed until the API groups they require are served: %s", strings.Join(skipped, "; "))
+
+	condition := NewHubCondition(operatorsv1.PrerequisitesMissing, metav1.ConditionTrue, PrerequisitesMissingReason, message)
+	ReplaceHubCondition(&m.Status, *condition)
+}
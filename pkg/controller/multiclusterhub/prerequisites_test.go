@@ -0,0 +1,58 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package multiclusterhub
+
+import (
+	"strings"
+	"testing"
+
+	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/subscription"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func Test_prerequisitesMissing(t *testing.T) {
+	mch := full_mch.DeepCopy()
+	r, err := getTestReconciler(mch)
+	if err != nil {
+		t.Fatalf("Failed to create test reconciler")
+	}
+	placement := schema.GroupVersion{Group: "cluster.open-cluster-management.io", Version: "v1alpha1"}
+	fakeDiscovery := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	r.discovery = fakeDiscovery
+	r.CacheSpec.Prerequisites = map[string][]schema.GroupVersion{"grc-sub": {placement}}
+
+	if r.prerequisitesMissing(mch, "search-prod-sub") {
+		t.Errorf("Component without prerequisites was skipped")
+	}
+
+	t.Run("Missing prerequisite", func(t *testing.T) {
+		result, err := r.ensureSubscription(mch, subscription.GRC(mch, map[string]string{}))
+		if result != nil || err != nil {
+			t.Fatalf("ensureSubscription() = %v, %v, want the component skipped", result, err)
+		}
+		c := GetHubCondition(mch.Status, operatorsv1.PrerequisitesMissing)
+		if c == nil || !strings.Contains(c.Message, "grc-sub ("+placement.String()+")") {
+			t.Errorf("PrerequisitesMissing condition = %v, want grc-sub listed", c)
+		}
+
+		components := getComponentStatuses(mch, nil, nil, nil, nil, r.state(mch).skippedComponents)
+		if s := components["grc-sub"]; !s.Available || s.Reason != PrerequisitesMissingReason {
+			t.Errorf("grc-sub status = %v, want skipped and not blocking", s)
+		}
+	})
+
+	t.Run("Prerequisite served", func(t *testing.T) {
+		fakeDiscovery.Resources = []*metav1.APIResourceList{{GroupVersion: placement.String()}}
+		if r.prerequisitesMissing(mch, "grc-sub") {
+			t.Errorf("Component was skipped once its prerequisites are served")
+		}
+		if c := GetHubCondition(mch.Status, operatorsv1.PrerequisitesMissing); c != nil {
+			t.Errorf("PrerequisitesMissing condition = %v, want removed", c)
+		}
+	})
+}
@@ -7,6 +7,9 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -14,6 +17,7 @@ import (
 	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/foundation"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/helmrepo"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/readiness"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/utils"
 	"github.com/open-cluster-management/multicloudhub-operator/version"
 	appsv1 "k8s.io/api/apps/v1"
@@ -63,6 +67,40 @@ const (
 	NamespaceTerminatingReason = "ManagedClusterNamespaceTerminating"
 	// ResourceRenderReason is added when an error occurs while rendering a deployable resource
 	ResourceRenderReason = "FailedRenderingResource"
+	// NodePortConflictReason is added when a node port requested for a hub service is already assigned
+	NodePortConflictReason = "NodePortConflict"
+	// InstallTimeoutReason is added when the hub fails to reach the running phase within the install timeout
+	InstallTimeoutReason = "InstallTimedOut"
+	// ResourceQuotaExceededReason is added when hub components request more resources than a namespace quota allows
+	ResourceQuotaExceededReason = "ResourceQuotaExceeded"
+	// UpdateDeferredReason is added when component image updates are waiting for the next update window
+	UpdateDeferredReason = "OutsideUpdateWindow"
+	// InvalidUpdateWindowReason is added when an update window override cannot be parsed
+	InvalidUpdateWindowReason = "InvalidUpdateWindow"
+	// ExternalChannelMissingReason is added when the hub is waiting for an externally managed channel to exist
+	ExternalChannelMissingReason = "ExternalChannelNotFound"
+	// ImageArchitectureMismatchReason is added when a component image does not support a schedulable node architecture
+	ImageArchitectureMismatchReason = "ImageArchitectureMismatch"
+	// EnvFromSourceMissingReason is added when a configmap or secret referenced as a component environment source is missing
+	EnvFromSourceMissingReason = "EnvFromSourceMissing"
+	// OwnershipConflictReason is added when a managed resource carries another controller's owner reference or installer labels
+	OwnershipConflictReason = "ConflictingOwner"
+	// InvalidImageTagSuffixReason is added when the image tag suffix override is not a legal tag fragment
+	InvalidImageTagSuffixReason = "InvalidImageTagSuffix"
+	// DisruptionBudgetReason is added when reducing a component's replicas would violate a PodDisruptionBudget
+	DisruptionBudgetReason = "PodDisruptionBudgetViolation"
+	// OperatorsNotReadyReason is added when the hub is waiting for required OLM operators to succeed
+	OperatorsNotReadyReason = "RequiredOperatorsNotReady"
+	// ImageDigestMismatchReason is added when the running pods of a component have run different image digests past the grace period
+	ImageDigestMismatchReason = "ImageDigestMismatch"
+	// WebhookTLSSecretMissingReason is added when the hub is waiting for an externally provided webhook TLS secret
+	WebhookTLSSecretMissingReason = "WebhookTLSSecretNotFound"
+	// ReconcileBackedOffReason is added when the hub exhausts its reconcile error budget and is no longer reconciled
+	ReconcileBackedOffReason = "ReconcileBackedOff"
+	// PrerequisitesMissingReason is added when a component is skipped because API groups it requires are not served
+	PrerequisitesMissingReason = "PrerequisiteAPIsNotServed"
+	// NamespaceMismatchReason is added when the hub is required to be in the operator namespace and is not
+	NamespaceMismatchReason = "OperatorNamespaceMismatch"
 )
 
 func getDeployments(m *operatorsv1.MultiClusterHub) []types.NamespacedName {
@@ -135,7 +173,7 @@ func (r *ReconcileMultiClusterHub) ComponentsAreRunning(m *operatorsv1.MultiClus
 	deployList, _ := r.listDeployments(trackedNamespaces)
 	hrList, _ := r.listHelmReleases(trackedNamespaces)
 	crList, _ := r.listCustomResources()
-	componentStatuses := getComponentStatuses(m, hrList, deployList, crList, nil)
+	componentStatuses := getComponentStatuses(m, hrList, deployList, crList, nil, r.state(m).skippedComponents)
 	delete(componentStatuses, ManagedClusterName)
 	return allComponentsSuccessful(componentStatuses)
 }
@@ -143,7 +181,7 @@ func (r *ReconcileMultiClusterHub) ComponentsAreRunning(m *operatorsv1.MultiClus
 // syncHubStatus checks if the status is up-to-date and sync it if necessary
 func (r *ReconcileMultiClusterHub) syncHubStatus(m *operatorsv1.MultiClusterHub, original *operatorsv1.MultiClusterHubStatus, allDeps []*appsv1.Deployment, allHRs []*subrelv1.HelmRelease, allCRs []*unstructured.Unstructured) (reconcile.Result, error) {
 	localCluster, err := r.ensureManagedClusterIsRunning(m)
-	newStatus := calculateStatus(m, allDeps, allHRs, allCRs, localCluster)
+	newStatus := calculateStatus(m, allDeps, allHRs, allCRs, localCluster, r.state(m).skippedComponents)
 	if reflect.DeepEqual(m.Status, original) {
 		log.Info("Status hasn't changed")
 		return reconcile.Result{}, nil
@@ -162,6 +200,7 @@ func (r *ReconcileMultiClusterHub) syncHubStatus(m *operatorsv1.MultiClusterHub,
 		log.Error(err, fmt.Sprintf("Failed to update %s/%s status ", m.Namespace, m.Name))
 		return reconcile.Result{}, err
 	}
+	r.notifyReady(m, original)
 
 	if m.Status.Phase != operatorsv1.HubRunning {
 		return reconcile.Result{RequeueAfter: resyncPeriod}, nil
@@ -170,12 +209,35 @@ func (r *ReconcileMultiClusterHub) syncHubStatus(m *operatorsv1.MultiClusterHub,
 	}
 }
 
-func calculateStatus(hub *operatorsv1.MultiClusterHub, allDeps []*appsv1.Deployment, allHRs []*subrelv1.HelmRelease, allCRs []*unstructured.Unstructured, importClusterStatus []interface{}) operatorsv1.MultiClusterHubStatus {
-	components := getComponentStatuses(hub, allHRs, allDeps, allCRs, importClusterStatus)
+// notifyReady sends the ready callback when a status update records the hub reaching the
+// running phase before any version was ever completed
+func (r *ReconcileMultiClusterHub) notifyReady(m *operatorsv1.MultiClusterHub, original *operatorsv1.MultiClusterHubStatus) {
+	url := utils.GetReadyCallbackURL(m)
+	if url == "" || original.CurrentVersion != "" || m.Status.Phase != operatorsv1.HubRunning {
+		return
+	}
+
+	configHash, err := utils.HubConfigHash(m, r.CacheSpec.ImageOverrides)
+	if err != nil {
+		log.Error(err, "Failed to compute hub configuration hash")
+	}
+	log.Info("Sending ready callback", "URL", url)
+	readiness.NotifyReady(url, readiness.Callback{
+		Name:       m.Name,
+		Namespace:  m.Namespace,
+		Version:    m.Status.CurrentVersion,
+		ConfigHash: configHash,
+	})
+}
+
+func calculateStatus(hub *operatorsv1.MultiClusterHub, allDeps []*appsv1.Deployment, allHRs []*subrelv1.HelmRelease, allCRs []*unstructured.Unstructured, importClusterStatus []interface{}, skipped map[string]string) operatorsv1.MultiClusterHubStatus {
+	components := getComponentStatuses(hub, allHRs, allDeps, allCRs, importClusterStatus, skipped)
 	status := operatorsv1.MultiClusterHubStatus{
-		CurrentVersion: hub.Status.CurrentVersion,
-		DesiredVersion: version.Version,
-		Components:     components,
+		CurrentVersion:   hub.Status.CurrentVersion,
+		DesiredVersion:   version.Version,
+		Components:       components,
+		InstallStartTime: hub.Status.InstallStartTime,
+		NextUpdateWindow: hub.Status.NextUpdateWindow,
 	}
 
 	// Set current version
@@ -223,11 +285,49 @@ func calculateStatus(hub *operatorsv1.MultiClusterHub, allDeps []*appsv1.Deploym
 		status.Phase = operatorsv1.HubUninstalling
 	} else {
 		status.Phase = aggregatePhase(status)
+		checkInstallTimeout(hub, &status)
+		if c := GetHubCondition(status, operatorsv1.Progressing); c != nil && c.Reason == ReconcileBackedOffReason {
+			status.Phase = operatorsv1.HubFailed
+		}
 	}
 
 	return status
 }
 
+// checkInstallTimeout records when the install began and marks the hub as failed if it has
+// not reached the running phase within the install timeout
+func checkInstallTimeout(hub *operatorsv1.MultiClusterHub, status *operatorsv1.MultiClusterHubStatus) {
+	if status.Phase != operatorsv1.HubInstalling {
+		if c := GetHubCondition(*status, operatorsv1.Progressing); c != nil && c.Reason == InstallTimeoutReason {
+			RemoveHubCondition(status, operatorsv1.Progressing)
+		}
+		return
+	}
+
+	if status.InstallStartTime == nil {
+		now := metav1.Now()
+		status.InstallStartTime = &now
+	}
+
+	timeout := utils.GetInstallTimeout(hub)
+	if timeout == 0 || time.Since(status.InstallStartTime.Time) < timeout {
+		return
+	}
+
+	var unready []string
+	for name, c := range status.Components {
+		if !successfulComponent(c) {
+			unready = append(unready, name)
+		}
+	}
+	sort.Strings(unready)
+
+	status.Phase = operatorsv1.HubFailed
+	message := fmt.Sprintf("Install did not complete within %s. Unready components: %s", timeout, strings.Join(unready, ", "))
+	failed := NewHubCondition(operatorsv1.Progressing, v1.ConditionFalse, InstallTimeoutReason, message)
+	SetHubCondition(status, *failed)
+}
+
 // filterDuplicateHRs removes multiple helmreleases owned by the same appsub, keeping the newest
 func filterDuplicateHRs(allHRs []*subrelv1.HelmRelease) []*subrelv1.HelmRelease {
 	keys := make(map[string]int)
@@ -263,7 +363,7 @@ func filterDuplicateHRs(allHRs []*subrelv1.HelmRelease) []*subrelv1.HelmRelease
 }
 
 // getComponentStatuses populates a complete list of the hub component statuses
-func getComponentStatuses(hub *operatorsv1.MultiClusterHub, allHRs []*subrelv1.HelmRelease, allDeps []*appsv1.Deployment, allCRs []*unstructured.Unstructured, importClusterStatus []interface{}) map[string]operatorsv1.StatusCondition {
+func getComponentStatuses(hub *operatorsv1.MultiClusterHub, allHRs []*subrelv1.HelmRelease, allDeps []*appsv1.Deployment, allCRs []*unstructured.Unstructured, importClusterStatus []interface{}, skipped map[string]string) map[string]operatorsv1.StatusCondition {
 	components := newComponentList(hub)
 
 	filteredHRs := filterDuplicateHRs(allHRs)
@@ -308,12 +408,32 @@ func getComponentStatuses(hub *operatorsv1.MultiClusterHub, allHRs []*subrelv1.H
 		}
 	}
 
+	// Skipped components do not hold back the hub
+	for name, missing := range skipped {
+		if _, ok := components[name]; ok {
+			components[name] = mapSkippedComponent(missing)
+		}
+	}
+
 	if !hub.Spec.DisableHubSelfManagement {
 		components["local-cluster"] = mapManagedClusterConditions(importClusterStatus)
 	}
 	return components
 }
 
+func mapSkippedComponent(missing string) operatorsv1.StatusCondition {
+	return operatorsv1.StatusCondition{
+		Kind:               "Subscription",
+		Type:               "Skipped",
+		Status:             metav1.ConditionTrue,
+		LastUpdateTime:     metav1.Now(),
+		LastTransitionTime: metav1.Now(),
+		Reason:             PrerequisitesMissingReason,
+		Message:            fmt.Sprintf("Not installed until these API groups are served: %s", missing),
+		Available:          true,
+	}
+}
+
 func successfulDeploy(d *appsv1.Deployment) bool {
 	for _, c := range d.Status.Conditions {
 		if c.Type == appsv1.DeploymentAvailable && c.Status == corev1.ConditionFalse {
@@ -325,6 +445,14 @@ func successfulDeploy(d *appsv1.Deployment) bool {
 		return false
 	}
 
+	// Replica counts are only current once the deployment controller has observed the latest spec
+	if d.Status.ObservedGeneration < d.Generation {
+		return false
+	}
+	if d.Spec.Replicas != nil && d.Status.AvailableReplicas < *d.Spec.Replicas {
+		return false
+	}
+
 	return true
 	// latest := latestDeployCondition(d.Status.Conditions)
 }
@@ -386,6 +514,9 @@ func mapDeployment(ds *appsv1.Deployment) operatorsv1.StatusCondition {
 			Message:            sub.Message,
 			Available:          false,
 		}
+		if ds.Spec.Replicas != nil {
+			ret.Message = strings.TrimSpace(fmt.Sprintf("%d/%d replicas available. %s", ds.Status.AvailableReplicas, *ds.Spec.Replicas, sub.Message))
+		}
 	}
 
 	return ret
@@ -609,6 +740,22 @@ func SetHubCondition(status *operatorsv1.MultiClusterHubStatus, condition operat
 	status.HubConditions = append(newConditions, condition)
 }
 
+// ReplaceHubCondition sets the status condition like SetHubCondition, but also replaces it when only its message
+// changes, so a message that lists current problems does not go stale.
+func ReplaceHubCondition(status *operatorsv1.MultiClusterHubStatus, condition operatorsv1.HubCondition) {
+	currentCond := GetHubCondition(*status, condition.Type)
+	if currentCond != nil && currentCond.Status == condition.Status && currentCond.Reason == condition.Reason &&
+		currentCond.Message == condition.Message {
+		return
+	}
+	// Do not update lastTransitionTime if the status of the condition doesn't change.
+	if currentCond != nil && currentCond.Status == condition.Status {
+		condition.LastTransitionTime = currentCond.LastTransitionTime
+	}
+	newConditions := filterOutCondition(status.HubConditions, condition.Type)
+	status.HubConditions = append(newConditions, condition)
+}
+
 // RemoveCRDCondition removes the status condition.
 func RemoveHubCondition(status *operatorsv1.MultiClusterHubStatus, condType operatorsv1.HubConditionType) {
 	status.HubConditions = filterOutCondition(status.HubConditions, condType)
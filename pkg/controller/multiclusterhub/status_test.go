@@ -192,6 +192,60 @@ var (
 	}
 )
 
+func Test_mapDeploymentReplicas(t *testing.T) {
+	replicas := int32(2)
+	newDeployment := func(generation, observed int64, available int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "ocm-controller", Generation: generation},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: observed,
+				AvailableReplicas:  available,
+				Conditions: []appsv1.DeploymentCondition{
+					{Type: appsv1.DeploymentAvailable, Status: "True", LastTransitionTime: v1.NewTime(time.Now())},
+					{Type: appsv1.DeploymentProgressing, Status: "True", Reason: "ReplicaSetUpdated"},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		dep     *appsv1.Deployment
+		want    bool
+		message string
+	}{
+		{
+			name: "All replicas available",
+			dep:  newDeployment(2, 2, 2),
+			want: true,
+		},
+		{
+			name:    "Replicas still starting",
+			dep:     newDeployment(2, 2, 1),
+			want:    false,
+			message: "1/2 replicas available.",
+		},
+		{
+			name:    "Spec not yet observed",
+			dep:     newDeployment(3, 2, 2),
+			want:    false,
+			message: "2/2 replicas available.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mapDeployment(tt.dep)
+			if got.Available != tt.want {
+				t.Errorf("mapDeployment() available = %v, want %v", got.Available, tt.want)
+			}
+			if !tt.want && got.Message != tt.message {
+				t.Errorf("mapDeployment() message = %q, want %q", got.Message, tt.message)
+			}
+		})
+	}
+}
+
 func TestSetHubCondition(t *testing.T) {
 	t.Run("Add single hubcondition", func(t *testing.T) {
 		m := &operatorsv1.MultiClusterHub{}
@@ -225,6 +279,25 @@ func TestSetHubCondition(t *testing.T) {
 	})
 }
 
+func TestReplaceHubCondition(t *testing.T) {
+	m := &operatorsv1.MultiClusterHub{}
+	ReplaceHubCondition(&m.Status, old2)
+
+	changed := new2
+	changed.Message = "Updated message"
+	ReplaceHubCondition(&m.Status, changed)
+	if len(m.Status.HubConditions) != 1 {
+		t.Fatalf("ReplaceHubCondition() expected %d hub conditions, got %d", 1, len(m.Status.HubConditions))
+	}
+	got := m.Status.HubConditions[0]
+	if got.Message != "Updated message" {
+		t.Errorf("ReplaceHubCondition() expected message %q, got %q", "Updated message", got.Message)
+	}
+	if !got.LastTransitionTime.Equal(&old2.LastTransitionTime) {
+		t.Errorf("ReplaceHubCondition() expected lastTransitionTime of %v, got %v", old2.LastTransitionTime, got.LastTransitionTime)
+	}
+}
+
 func TestGetHubCondition(t *testing.T) {
 	testStatus := operatorsv1.MultiClusterHubStatus{
 		HubConditions: []operatorsv1.HubCondition{new},
@@ -469,3 +542,89 @@ func Test_aggregatePhase(t *testing.T) {
 		})
 	}
 }
+
+func Test_checkInstallTimeout(t *testing.T) {
+	unavailable := operatorsv1.StatusCondition{Type: "Available", Status: v1.ConditionFalse}
+	started := metav1.NewTime(time.Now().Add(-time.Hour))
+
+	withTimeout := func(d time.Duration) *operatorsv1.MultiClusterHub {
+		return &operatorsv1.MultiClusterHub{
+			Spec: operatorsv1.MultiClusterHubSpec{
+				Overrides: &operatorsv1.Overrides{InstallTimeout: &metav1.Duration{Duration: d}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		hub        *operatorsv1.MultiClusterHub
+		status     operatorsv1.MultiClusterHubStatus
+		wantPhase  operatorsv1.HubPhaseType
+		wantReason string
+	}{
+		{
+			name: "No timeout configured",
+			hub:  &operatorsv1.MultiClusterHub{},
+			status: operatorsv1.MultiClusterHubStatus{
+				Phase:            operatorsv1.HubInstalling,
+				InstallStartTime: &started,
+				Components:       map[string]operatorsv1.StatusCondition{"foo": unavailable},
+			},
+			wantPhase: operatorsv1.HubInstalling,
+		},
+		{
+			name: "Within timeout",
+			hub:  withTimeout(2 * time.Hour),
+			status: operatorsv1.MultiClusterHubStatus{
+				Phase:            operatorsv1.HubInstalling,
+				InstallStartTime: &started,
+				Components:       map[string]operatorsv1.StatusCondition{"foo": unavailable},
+			},
+			wantPhase: operatorsv1.HubInstalling,
+		},
+		{
+			name: "Timeout exceeded",
+			hub:  withTimeout(30 * time.Minute),
+			status: operatorsv1.MultiClusterHubStatus{
+				Phase:            operatorsv1.HubInstalling,
+				InstallStartTime: &started,
+				Components:       map[string]operatorsv1.StatusCondition{"foo": unavailable},
+			},
+			wantPhase:  operatorsv1.HubFailed,
+			wantReason: InstallTimeoutReason,
+		},
+		{
+			name: "Recovered after timeout",
+			hub:  withTimeout(30 * time.Minute),
+			status: operatorsv1.MultiClusterHubStatus{
+				Phase:            operatorsv1.HubRunning,
+				InstallStartTime: &started,
+				HubConditions:    []operatorsv1.HubCondition{*NewHubCondition(operatorsv1.Progressing, v1.ConditionFalse, InstallTimeoutReason, "")},
+			},
+			wantPhase: operatorsv1.HubRunning,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkInstallTimeout(tt.hub, &tt.status)
+			if tt.status.Phase != tt.wantPhase {
+				t.Errorf("checkInstallTimeout() phase = %v, want %v", tt.status.Phase, tt.wantPhase)
+			}
+			c := GetHubCondition(tt.status, operatorsv1.Progressing)
+			if tt.wantReason == "" && c != nil && c.Reason == InstallTimeoutReason {
+				t.Errorf("checkInstallTimeout() left the %s condition in place", InstallTimeoutReason)
+			}
+			if tt.wantReason != "" && (c == nil || c.Reason != tt.wantReason) {
+				t.Errorf("checkInstallTimeout() condition = %v, want reason %v", c, tt.wantReason)
+			}
+		})
+	}
+
+	t.Run("Records install start time", func(t *testing.T) {
+		status := operatorsv1.MultiClusterHubStatus{Phase: operatorsv1.HubInstalling}
+		checkInstallTimeout(&operatorsv1.MultiClusterHub{}, &status)
+		if status.InstallStartTime == nil {
+			t.Errorf("checkInstallTimeout() did not record the install start time")
+		}
+	})
+}
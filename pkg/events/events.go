@@ -0,0 +1,128 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// Package events provides the event recorder used by the multiclusterhub reconciler
+package events
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	// CreatedReason is recorded when the operator creates a managed resource
+	CreatedReason = "ResourceCreated"
+	// CreateFailedReason is recorded when the operator fails to create a managed resource
+	CreateFailedReason = "ResourceCreateFailed"
+	// UpdatedReason is recorded when the operator updates a managed resource
+	UpdatedReason = "ResourceUpdated"
+	// UpdateFailedReason is recorded when the operator fails to update a managed resource
+	UpdateFailedReason = "ResourceUpdateFailed"
+
+	// DefaultThrottleWindow is the window during which identical events are only emitted once
+	DefaultThrottleWindow = 5 * time.Minute
+
+	// maxTrackedEvents bounds the number of distinct events remembered before expired entries are pruned
+	maxTrackedEvents = 1000
+)
+
+// throttledEvent tracks an emitted event and how many identical events have been dropped since
+type throttledEvent struct {
+	emitted    time.Time
+	suppressed int
+}
+
+// ThrottledRecorder wraps an EventRecorder so identical events (same object, type, reason, and
+// message) are emitted at most once per window. Repeats within the window are counted and reported
+// as a single aggregated event the next time the event is emitted after the window expires.
+type ThrottledRecorder struct {
+	recorder record.EventRecorder
+	window   time.Duration
+	now      func() time.Time
+
+	mu     sync.Mutex
+	events map[string]*throttledEvent
+}
+
+var _ record.EventRecorder = &ThrottledRecorder{}
+
+// NewThrottledRecorder returns a recorder that deduplicates identical events over the given window
+func NewThrottledRecorder(recorder record.EventRecorder, window time.Duration) *ThrottledRecorder {
+	return &ThrottledRecorder{
+		recorder: recorder,
+		window:   window,
+		now:      time.Now,
+		events:   make(map[string]*throttledEvent),
+	}
+}
+
+// Event records an event unless an identical one was recorded within the throttle window
+func (t *ThrottledRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if message, ok := t.allow(object, eventtype, reason, message); ok {
+		t.recorder.Event(object, eventtype, reason, message)
+	}
+}
+
+// Eventf is just like Event, but with Sprintf for the message field
+func (t *ThrottledRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	t.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// AnnotatedEventf is just like Eventf, but with annotations attached
+func (t *ThrottledRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	if message, ok := t.allow(object, eventtype, reason, fmt.Sprintf(messageFmt, args...)); ok {
+		t.recorder.AnnotatedEventf(object, annotations, eventtype, reason, "%s", message)
+	}
+}
+
+// allow returns whether the event should be emitted, along with the message to emit
+func (t *ThrottledRecorder) allow(object runtime.Object, eventtype, reason, message string) (string, bool) {
+	key := eventKey(object, eventtype, reason, message)
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.events[key]
+	if ok && now.Sub(e.emitted) < t.window {
+		e.suppressed++
+		return "", false
+	}
+
+	if ok && e.suppressed > 0 {
+		message = fmt.Sprintf("%s (repeated %d times in the last %s)", message, e.suppressed, t.window)
+	}
+
+	if len(t.events) >= maxTrackedEvents {
+		t.prune(now)
+	}
+	t.events[key] = &throttledEvent{emitted: now}
+	return message, true
+}
+
+// prune forgets events whose throttle window has expired. Must be called with the lock held.
+func (t *ThrottledRecorder) prune(now time.Time) {
+	for k, e := range t.events {
+		if now.Sub(e.emitted) >= t.window {
+			delete(t.events, k)
+		}
+	}
+}
+
+// eventKey uniquely identifies an event by its involved object and contents
+func eventKey(object runtime.Object, eventtype, reason, message string) string {
+	id := ""
+	if accessor, err := meta.Accessor(object); err == nil {
+		id = fmt.Sprintf("%s/%s/%s", accessor.GetNamespace(), accessor.GetName(), accessor.GetUID())
+	}
+	kind := ""
+	if object != nil {
+		kind = object.GetObjectKind().GroupVersionKind().Kind
+	}
+	return fmt.Sprintf("%s|%s|%s|%s|%s", kind, id, eventtype, reason, message)
+}
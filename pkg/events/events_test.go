@@ -0,0 +1,87 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package events
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func drain(f *record.FakeRecorder) []string {
+	var ret []string
+	for {
+		select {
+		case e := <-f.Events:
+			ret = append(ret, e)
+		default:
+			return ret
+		}
+	}
+}
+
+func TestThrottledRecorder(t *testing.T) {
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test", UID: "1"}}
+	other := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "test", UID: "2"}}
+
+	fake := record.NewFakeRecorder(100)
+	tr := NewThrottledRecorder(fake, time.Minute)
+	now := time.Now()
+	tr.now = func() time.Time { return now }
+
+	for i := 0; i < 10; i++ {
+		tr.Eventf(obj, corev1.EventTypeWarning, UpdateFailedReason, "Failed to update %s", "deployment")
+	}
+	if got := drain(fake); len(got) != 1 {
+		t.Fatalf("expected 1 event within the window, got %d: %v", len(got), got)
+	}
+
+	// Distinct objects and messages are not throttled together
+	tr.Event(other, corev1.EventTypeWarning, UpdateFailedReason, "Failed to update deployment")
+	tr.Event(obj, corev1.EventTypeWarning, UpdateFailedReason, "Failed to update service")
+	if got := drain(fake); len(got) != 2 {
+		t.Fatalf("expected 2 distinct events, got %d: %v", len(got), got)
+	}
+
+	// After the window expires the event is emitted with an aggregated count
+	now = now.Add(2 * time.Minute)
+	tr.Event(obj, corev1.EventTypeWarning, UpdateFailedReason, "Failed to update deployment")
+	got := drain(fake)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 event after the window, got %d: %v", len(got), got)
+	}
+	if !strings.Contains(got[0], "repeated 9 times") {
+		t.Errorf("expected aggregated repeat count in event, got %q", got[0])
+	}
+
+	// Without repeats no count is reported
+	now = now.Add(2 * time.Minute)
+	tr.Event(obj, corev1.EventTypeWarning, UpdateFailedReason, "Failed to update deployment")
+	got = drain(fake)
+	if len(got) != 1 || strings.Contains(got[0], "repeated") {
+		t.Errorf("expected a single plain event, got %v", got)
+	}
+}
+
+func TestThrottledRecorderPrune(t *testing.T) {
+	fake := record.NewFakeRecorder(maxTrackedEvents + 10)
+	tr := NewThrottledRecorder(fake, time.Minute)
+	now := time.Now()
+	tr.now = func() time.Time { return now }
+
+	for i := 0; i < maxTrackedEvents; i++ {
+		obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: strings.Repeat("a", i+1)}}
+		tr.Event(obj, corev1.EventTypeNormal, CreatedReason, "created")
+	}
+
+	now = now.Add(2 * time.Minute)
+	tr.Event(&corev1.ConfigMap{}, corev1.EventTypeNormal, CreatedReason, "created")
+	if len(tr.events) != 1 {
+		t.Errorf("expected expired events to be pruned, %d still tracked", len(tr.events))
+	}
+}
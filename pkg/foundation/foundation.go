@@ -48,6 +48,17 @@ func defaultLabels(app string) map[string]string {
 	}
 }
 
+// secretVolumes maps the names of secret volumes to the secrets they mount
+func secretVolumes(volumes []corev1.Volume) map[string]string {
+	secrets := map[string]string{}
+	for _, v := range volumes {
+		if v.Secret != nil {
+			secrets[v.Name] = v.Secret.SecretName
+		}
+	}
+	return secrets
+}
+
 func defaultTolerations() []corev1.Toleration {
 	return []corev1.Toleration{
 		{
@@ -58,6 +69,11 @@ func defaultTolerations() []corev1.Toleration {
 	}
 }
 
+// tolerations returns the default tolerations followed by those from the CR spec
+func tolerations(m *operatorsv1.MultiClusterHub) []corev1.Toleration {
+	return append(defaultTolerations(), m.Spec.Tolerations...)
+}
+
 func getReplicaCount(mch *operatorsv1.MultiClusterHub) int32 {
 	if mch.Spec.AvailabilityConfig == operatorsv1.HABasic {
 		return 1
@@ -85,6 +101,12 @@ func ValidateDeployment(m *operatorsv1.MultiClusterHub, overrides map[string]str
 		}
 	}
 
+	// verify OpenShift image triggers do not override the component images
+	if utils.RemoveImageTriggers(found.Annotations) {
+		log.Info("Removing conflicting image trigger annotation", "Annotation", utils.AnnotationImageTriggers)
+		needsUpdate = true
+	}
+
 	// verify image repository and suffix
 	if container.Image != Image(overrides) {
 		log.Info("Enforcing image repo and suffix from CR spec")
@@ -128,9 +150,31 @@ func ValidateDeployment(m *operatorsv1.MultiClusterHub, overrides map[string]str
 		needsUpdate = true
 	}
 
-	if !reflect.DeepEqual(pod.Tolerations, defaultTolerations()) {
+	if !reflect.DeepEqual(container.EnvFrom, utils.GetContainerEnvFrom(expected)) {
+		log.Info("Enforcing container environment variable sources")
+		container.EnvFrom = utils.GetContainerEnvFrom(expected)
+		needsUpdate = true
+	}
+
+	expectedSeccomp := expected.Spec.Template.Spec.SecurityContext.SeccompProfile
+	if pod.SecurityContext == nil || !reflect.DeepEqual(pod.SecurityContext.SeccompProfile, expectedSeccomp) {
+		log.Info("Enforcing pod seccomp profile")
+		if pod.SecurityContext == nil {
+			pod.SecurityContext = &corev1.PodSecurityContext{}
+		}
+		pod.SecurityContext.SeccompProfile = expectedSeccomp
+		needsUpdate = true
+	}
+
+	if !reflect.DeepEqual(pod.Tolerations, tolerations(m)) {
 		log.Info("Enforcing spec tolerations")
-		pod.Tolerations = defaultTolerations()
+		pod.Tolerations = tolerations(m)
+		needsUpdate = true
+	}
+
+	if !reflect.DeepEqual(secretVolumes(pod.Volumes), secretVolumes(expected.Spec.Template.Spec.Volumes)) {
+		log.Info("Enforcing pod secret volumes")
+		pod.Volumes = expected.Spec.Template.Spec.Volumes
 		needsUpdate = true
 	}
 
@@ -142,7 +186,13 @@ func ValidateDeployment(m *operatorsv1.MultiClusterHub, overrides map[string]str
 	}
 
 	expectedRequestResourceList := utils.GetContainerRequestResources(expected)
-	if !reflect.DeepEqual(container.Resources.Requests.Cpu().MilliValue(), expectedRequestResourceList.Cpu().MilliValue()) {
+	if utils.MergeContainerResources(m) {
+		if resources, changed := utils.MergeResources(container.Resources, expected.Spec.Template.Spec.Containers[0].Resources); changed {
+			log.Info("Enforcing operator-set container resources while keeping added resources")
+			container.Resources = resources
+			needsUpdate = true
+		}
+	} else if !reflect.DeepEqual(container.Resources.Requests.Cpu().MilliValue(), expectedRequestResourceList.Cpu().MilliValue()) {
 		log.Info("Enforcing container resource requests and limits")
 		container.Resources.Requests = expectedRequestResourceList
 		needsUpdate = true
@@ -11,6 +11,7 @@ import (
 	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -53,6 +54,20 @@ func TestValidateDeployment(t *testing.T) {
 	dep6 := dep.DeepCopy()
 	dep6.Spec.Template.Spec.Tolerations = nil
 
+	// 8. Modified seccomp profile
+	dep7 := dep.DeepCopy()
+	dep7.Spec.Template.Spec.SecurityContext.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined}
+
+	// 9. Added container resource limit with merged resources
+	mergeMch := mch.DeepCopy()
+	mergeMch.Spec.Overrides = &operatorsv1.Overrides{MergeContainerResources: true}
+	dep8 := dep.DeepCopy()
+	dep8.Spec.Template.Spec.Containers[0].Resources.Limits[corev1.ResourceCPU] = resource.MustParse("1")
+
+	// 10. Modified operator-set container resource limit with merged resources
+	dep9 := dep8.DeepCopy()
+	dep9.Spec.Template.Spec.Containers[0].Resources.Limits[corev1.ResourceMemory] = resource.MustParse("4Gi")
+
 	type args struct {
 		m   *operatorsv1.MultiClusterHub
 		dep *appsv1.Deployment
@@ -105,6 +120,24 @@ func TestValidateDeployment(t *testing.T) {
 			want:  dep,
 			want1: true,
 		},
+		{
+			name:  "Modified seccomp profile",
+			args:  args{mch, dep7},
+			want:  dep,
+			want1: true,
+		},
+		{
+			name:  "Added resource limit kept",
+			args:  args{mergeMch, dep8},
+			want:  dep8,
+			want1: false,
+		},
+		{
+			name:  "Operator-set resource limit enforced",
+			args:  args{mergeMch, dep9},
+			want:  dep8,
+			want1: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
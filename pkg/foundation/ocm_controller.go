@@ -40,9 +40,10 @@ func OCMControllerDeployment(m *operatorsv1.MultiClusterHub, overrides map[strin
 				},
 				Spec: corev1.PodSpec{
 					ImagePullSecrets:   []corev1.LocalObjectReference{{Name: m.Spec.ImagePullSecret}},
+					SecurityContext:    utils.GetPodSecurityContext(m),
 					ServiceAccountName: ServiceAccount,
 					NodeSelector:       m.Spec.NodeSelector,
-					Tolerations:        defaultTolerations(),
+					Tolerations:        tolerations(m),
 					Affinity:           utils.DistributePods("ocm-antiaffinity-selector", OCMControllerName),
 					Volumes: []corev1.Volume{
 						{
@@ -55,6 +56,7 @@ func OCMControllerDeployment(m *operatorsv1.MultiClusterHub, overrides map[strin
 					Containers: []corev1.Container{{
 						Image:           Image(overrides),
 						ImagePullPolicy: utils.GetImagePullPolicy(m),
+						EnvFrom:         utils.GetEnvFrom(m, OCMControllerName),
 						Name:            OCMControllerName,
 						Args: []string{
 							"/controller",
@@ -48,8 +48,9 @@ func OCMProxyServerDeployment(m *operatorsv1.MultiClusterHub, overrides map[stri
 				},
 				Spec: corev1.PodSpec{
 					ImagePullSecrets:   []corev1.LocalObjectReference{{Name: m.Spec.ImagePullSecret}},
+					SecurityContext:    utils.GetPodSecurityContext(m),
 					ServiceAccountName: ServiceAccount,
-					Tolerations:        defaultTolerations(),
+					Tolerations:        tolerations(m),
 					NodeSelector:       m.Spec.NodeSelector,
 					Affinity:           utils.DistributePods("ocm-antiaffinity-selector", OCMProxyServerName),
 					Volumes: []corev1.Volume{
@@ -63,6 +64,7 @@ func OCMProxyServerDeployment(m *operatorsv1.MultiClusterHub, overrides map[stri
 					Containers: []corev1.Container{{
 						Image:           Image(overrides),
 						ImagePullPolicy: utils.GetImagePullPolicy(m),
+						EnvFrom:         utils.GetEnvFrom(m, OCMProxyServerName),
 						Name:            OCMProxyServerName,
 						Args: []string{
 							"/proxyserver",
@@ -132,7 +134,9 @@ func OCMProxyServerService(m *operatorsv1.MultiClusterHub) *corev1.Service {
 				Protocol:   corev1.ProtocolTCP,
 				Port:       443,
 				TargetPort: intstr.FromInt(6443),
+				NodePort:   utils.GetNodePort(m, OCMProxyServerName),
 			}},
+			Type: utils.GetServiceType(m),
 		},
 	}
 
@@ -18,6 +18,17 @@ import (
 // WebhookName is the name of the foundation webhook deployment
 const WebhookName string = "ocm-webhook"
 
+// WebhookSecretName is the name of the operator generated webhook serving certificate secret
+const WebhookSecretName string = "ocm-webhook-secret"
+
+// webhookSecretName returns the externally provided webhook TLS secret, or the operator generated one
+func webhookSecretName(m *operatorsv1.MultiClusterHub) string {
+	if name := utils.GetWebhookTLSSecret(m); name != "" {
+		return name
+	}
+	return WebhookSecretName
+}
+
 // WebhookDeployment creates the deployment for the foundation webhook
 func WebhookDeployment(m *operatorsv1.MultiClusterHub, overrides map[string]string) *appsv1.Deployment {
 	replicas := getReplicaCount(m)
@@ -39,21 +50,23 @@ func WebhookDeployment(m *operatorsv1.MultiClusterHub, overrides map[string]stri
 				},
 				Spec: corev1.PodSpec{
 					ImagePullSecrets:   []corev1.LocalObjectReference{{Name: m.Spec.ImagePullSecret}},
+					SecurityContext:    utils.GetPodSecurityContext(m),
 					ServiceAccountName: ServiceAccount,
-					Tolerations:        defaultTolerations(),
+					Tolerations:        tolerations(m),
 					NodeSelector:       m.Spec.NodeSelector,
 					Affinity:           utils.DistributePods("ocm-antiaffinity-selector", WebhookName),
 					Volumes: []corev1.Volume{
 						{
 							Name: "webhook-cert",
 							VolumeSource: corev1.VolumeSource{
-								Secret: &corev1.SecretVolumeSource{SecretName: "ocm-webhook-secret"},
+								Secret: &corev1.SecretVolumeSource{SecretName: webhookSecretName(m)},
 							},
 						},
 					},
 					Containers: []corev1.Container{{
 						Image:           Image(overrides),
 						ImagePullPolicy: utils.GetImagePullPolicy(m),
+						EnvFrom:         utils.GetEnvFrom(m, WebhookName),
 						Name:            WebhookName,
 						Args: []string{
 							"/webhook",
@@ -116,7 +129,9 @@ func WebhookService(m *operatorsv1.MultiClusterHub) *corev1.Service {
 			Ports: []corev1.ServicePort{{
 				Port:       443,
 				TargetPort: intstr.FromInt(8000),
+				NodePort:   utils.GetNodePort(m, WebhookName),
 			}},
+			Type: utils.GetServiceType(m),
 		},
 	}
 
@@ -31,6 +31,25 @@ func TestWebhookDeployment(t *testing.T) {
 	t.Run("MCH with only required values", func(t *testing.T) {
 		_ = WebhookDeployment(essentialsOnly, ovr)
 	})
+
+	t.Run("External webhook TLS secret", func(t *testing.T) {
+		mch := essentialsOnly.DeepCopy()
+		if name := WebhookDeployment(mch, ovr).Spec.Template.Spec.Volumes[0].Secret.SecretName; name != WebhookSecretName {
+			t.Errorf("expected webhook secret %s, got %s", WebhookSecretName, name)
+		}
+		mch.Spec.Overrides = &operatorsv1.Overrides{WebhookTLSSecret: "external-webhook-tls"}
+		dep := WebhookDeployment(mch, ovr)
+		if name := dep.Spec.Template.Spec.Volumes[0].Secret.SecretName; name != "external-webhook-tls" {
+			t.Errorf("expected webhook secret %s, got %s", "external-webhook-tls", name)
+		}
+
+		// Switching to an external secret updates an existing deployment
+		found := WebhookDeployment(essentialsOnly, ovr)
+		updated, needsUpdate := ValidateDeployment(mch, ovr, dep, found)
+		if !needsUpdate || updated.Spec.Template.Spec.Volumes[0].Secret.SecretName != "external-webhook-tls" {
+			t.Errorf("expected the webhook secret volume to be updated")
+		}
+	})
 }
 
 func TestWebhookService(t *testing.T) {
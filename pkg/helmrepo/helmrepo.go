@@ -29,6 +29,15 @@ var HelmRepoName = "multiclusterhub-repo"
 // Port of helm repo service
 var Port = 3000
 
+// CacheClaimName is the name of the persistent volume claim backing the chart cache
+var CacheClaimName = HelmRepoName + "-cache"
+
+// CachePath is where the chart cache volume is mounted in the helm repo container
+var CachePath = "/app/cache"
+
+// defaultCacheSize is the storage requested for the chart cache when the CR does not set a size
+var defaultCacheSize = resource.MustParse("10Gi")
+
 // Version of helm repo image
 
 func labels() map[string]string {
@@ -38,7 +47,7 @@ func labels() map[string]string {
 	}
 }
 
-func tolerations() []corev1.Toleration {
+func defaultTolerations() []corev1.Toleration {
 	return []corev1.Toleration{
 		{
 			Effect:   "NoSchedule",
@@ -48,6 +57,11 @@ func tolerations() []corev1.Toleration {
 	}
 }
 
+// tolerations returns the default tolerations followed by those from the CR spec
+func tolerations(m *operatorsv1.MultiClusterHub) []corev1.Toleration {
+	return append(defaultTolerations(), m.Spec.Tolerations...)
+}
+
 // Image returns image reference for multiclusterhub-repo
 func Image(overrides map[string]string) string {
 	return overrides[ImageKey]
@@ -76,6 +90,7 @@ func Deployment(m *operatorsv1.MultiClusterHub, overrides map[string]string) *ap
 					Containers: []corev1.Container{{
 						Image:           Image(overrides),
 						ImagePullPolicy: utils.GetImagePullPolicy(m),
+						EnvFrom:         utils.GetEnvFrom(m, HelmRepoName),
 						Name:            HelmRepoName,
 						Ports: []corev1.ContainerPort{{
 							ContainerPort: int32(Port),
@@ -125,20 +140,75 @@ func Deployment(m *operatorsv1.MultiClusterHub, overrides map[string]string) *ap
 					}},
 					ImagePullSecrets: []corev1.LocalObjectReference{{Name: m.Spec.ImagePullSecret}},
 					NodeSelector:     m.Spec.NodeSelector,
-					Tolerations:      tolerations(),
+					Tolerations:      tolerations(m),
 					Affinity:         utils.DistributePods("ocm-antiaffinity-selector", HelmRepoName),
+					SecurityContext:  utils.GetPodSecurityContext(m),
 					// ServiceAccountName: "default",
 				},
 			},
 		},
 	}
 
+	// Mount the chart cache claim. Recreate keeps the old and new pods from attaching the volume at once
+	if utils.GetPersistence(m) != nil {
+		dep.Spec.Strategy = appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+		dep.Spec.Template.Spec.Volumes = []corev1.Volume{{
+			Name: "cache",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: CacheClaimName},
+			},
+		}}
+		dep.Spec.Template.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{{
+			Name:      "cache",
+			MountPath: CachePath,
+		}}
+	}
+
 	dep.SetOwnerReferences([]metav1.OwnerReference{
 		*metav1.NewControllerRef(m, m.GetObjectKind().GroupVersionKind()),
 	})
 	return dep
 }
 
+// PersistentVolumeClaim for the helm repo chart cache, configured by the persistence CR override
+func PersistentVolumeClaim(m *operatorsv1.MultiClusterHub) *corev1.PersistentVolumeClaim {
+	p := utils.GetPersistence(m)
+	if p == nil {
+		p = &operatorsv1.PersistenceConfig{}
+	}
+	size := defaultCacheSize
+	if p.Size != nil {
+		size = *p.Size
+	}
+	accessModes := p.AccessModes
+	if len(accessModes) == 0 {
+		accessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      CacheClaimName,
+			Namespace: m.Namespace,
+			Labels:    labels(),
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: accessModes,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: size},
+			},
+		},
+	}
+	if p.StorageClassName != "" {
+		storageClassName := p.StorageClassName
+		pvc.Spec.StorageClassName = &storageClassName
+	}
+
+	pvc.SetOwnerReferences([]metav1.OwnerReference{
+		*metav1.NewControllerRef(m, m.GetObjectKind().GroupVersionKind()),
+	})
+	return pvc
+}
+
 // Service for the helm repo serving charts
 func Service(m *operatorsv1.MultiClusterHub) *corev1.Service {
 	labels := labels()
@@ -147,6 +217,7 @@ func Service(m *operatorsv1.MultiClusterHub) *corev1.Service {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      HelmRepoName,
 			Namespace: m.Namespace,
+			Labels:    labels,
 		},
 		Spec: corev1.ServiceSpec{
 			Selector: labels,
@@ -154,8 +225,9 @@ func Service(m *operatorsv1.MultiClusterHub) *corev1.Service {
 				Protocol:   corev1.ProtocolTCP,
 				Port:       int32(Port),
 				TargetPort: intstr.FromInt(Port),
+				NodePort:   utils.GetNodePort(m, HelmRepoName),
 			}},
-			Type: corev1.ServiceTypeClusterIP,
+			Type: utils.GetServiceType(m),
 		},
 	}
 
@@ -184,6 +256,12 @@ func ValidateDeployment(m *operatorsv1.MultiClusterHub, overrides map[string]str
 		}
 	}
 
+	// verify OpenShift image triggers do not override the component images
+	if utils.RemoveImageTriggers(found.Annotations) {
+		log.Info("Removing conflicting image trigger annotation", "Annotation", utils.AnnotationImageTriggers)
+		needsUpdate = true
+	}
+
 	// verify image repository and suffix
 	if container.Image != Image(overrides) {
 		log.Info("Enforcing image repo and suffix from CR spec")
@@ -227,6 +305,22 @@ func ValidateDeployment(m *operatorsv1.MultiClusterHub, overrides map[string]str
 		needsUpdate = true
 	}
 
+	if !reflect.DeepEqual(container.EnvFrom, utils.GetContainerEnvFrom(expected)) {
+		log.Info("Enforcing container environment variable sources")
+		container.EnvFrom = utils.GetContainerEnvFrom(expected)
+		needsUpdate = true
+	}
+
+	expectedSeccomp := expected.Spec.Template.Spec.SecurityContext.SeccompProfile
+	if pod.SecurityContext == nil || !reflect.DeepEqual(pod.SecurityContext.SeccompProfile, expectedSeccomp) {
+		log.Info("Enforcing pod seccomp profile")
+		if pod.SecurityContext == nil {
+			pod.SecurityContext = &corev1.PodSecurityContext{}
+		}
+		pod.SecurityContext.SeccompProfile = expectedSeccomp
+		needsUpdate = true
+	}
+
 	if !reflect.DeepEqual(container.VolumeMounts, utils.GetContainerVolumeMounts(expected)) {
 		log.Info("Enforcing container volume mounts")
 		vms := utils.GetContainerVolumeMounts(expected)
@@ -234,11 +328,39 @@ func ValidateDeployment(m *operatorsv1.MultiClusterHub, overrides map[string]str
 		needsUpdate = true
 	}
 
-	if !reflect.DeepEqual(pod.Tolerations, tolerations()) {
+	if !reflect.DeepEqual(pod.Volumes, expected.Spec.Template.Spec.Volumes) {
+		log.Info("Enforcing pod volumes")
+		pod.Volumes = expected.Spec.Template.Spec.Volumes
+		needsUpdate = true
+	}
+
+	if strategyType(found) != strategyType(expected) {
+		log.Info("Enforcing deployment strategy", "Strategy", strategyType(expected))
+		found.Spec.Strategy = appsv1.DeploymentStrategy{Type: strategyType(expected)}
+		needsUpdate = true
+	}
+
+	if !reflect.DeepEqual(pod.Tolerations, tolerations(m)) {
 		log.Info("Enforcing spec tolerations")
-		pod.Tolerations = tolerations()
+		pod.Tolerations = tolerations(m)
 		needsUpdate = true
 	}
 
+	if utils.MergeContainerResources(m) {
+		if resources, changed := utils.MergeResources(container.Resources, expected.Spec.Template.Spec.Containers[0].Resources); changed {
+			log.Info("Enforcing operator-set container resources while keeping added resources")
+			container.Resources = resources
+			needsUpdate = true
+		}
+	}
+
 	return found, needsUpdate
 }
+
+// strategyType returns the deployment strategy type, which the API server defaults to RollingUpdate
+func strategyType(dep *appsv1.Deployment) appsv1.DeploymentStrategyType {
+	if dep.Spec.Strategy.Type == "" {
+		return appsv1.RollingUpdateDeploymentStrategyType
+	}
+	return dep.Spec.Strategy.Type
+}
@@ -9,8 +9,10 @@ import (
 	"testing"
 
 	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
+	"github.com/open-cluster-management/multicloudhub-operator/pkg/utils"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -55,6 +57,62 @@ func TestService(t *testing.T) {
 	})
 }
 
+func TestPersistentVolumeClaim(t *testing.T) {
+	mch := &operatorsv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "testName", Namespace: "testNS"},
+		Spec: operatorsv1.MultiClusterHubSpec{
+			Overrides: &operatorsv1.Overrides{Persistence: &operatorsv1.PersistenceConfig{}},
+		},
+	}
+
+	t.Run("Defaults", func(t *testing.T) {
+		pvc := PersistentVolumeClaim(mch)
+		if size := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; size.Cmp(resource.MustParse("10Gi")) != 0 {
+			t.Errorf("expected size 10Gi, got %s", size.String())
+		}
+		if !reflect.DeepEqual(pvc.Spec.AccessModes, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}) {
+			t.Errorf("expected access modes ReadWriteOnce, got %v", pvc.Spec.AccessModes)
+		}
+		if pvc.Spec.StorageClassName != nil {
+			t.Errorf("expected default storage class, got %s", *pvc.Spec.StorageClassName)
+		}
+		if ref := pvc.GetOwnerReferences(); ref[0].Name != "testName" {
+			t.Errorf("expected ownerReference %s, got %s", "testName", ref[0].Name)
+		}
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		size := resource.MustParse("20Gi")
+		m := mch.DeepCopy()
+		m.Spec.Overrides.Persistence = &operatorsv1.PersistenceConfig{
+			Size:             &size,
+			StorageClassName: "gp2",
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+		}
+		pvc := PersistentVolumeClaim(m)
+		if got := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; got.Cmp(size) != 0 {
+			t.Errorf("expected size 20Gi, got %s", got.String())
+		}
+		if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName != "gp2" {
+			t.Errorf("expected storage class gp2, got %v", pvc.Spec.StorageClassName)
+		}
+		if !reflect.DeepEqual(pvc.Spec.AccessModes, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}) {
+			t.Errorf("expected access modes ReadWriteMany, got %v", pvc.Spec.AccessModes)
+		}
+	})
+
+	t.Run("Mounted with Recreate strategy", func(t *testing.T) {
+		dep := Deployment(mch, map[string]string{})
+		if dep.Spec.Strategy.Type != appsv1.RecreateDeploymentStrategyType {
+			t.Errorf("expected Recreate strategy, got %s", dep.Spec.Strategy.Type)
+		}
+		volumes := dep.Spec.Template.Spec.Volumes
+		if len(volumes) != 1 || volumes[0].PersistentVolumeClaim == nil || volumes[0].PersistentVolumeClaim.ClaimName != CacheClaimName {
+			t.Errorf("expected claim %s mounted, got %v", CacheClaimName, volumes)
+		}
+	})
+}
+
 func TestValidateDeployment(t *testing.T) {
 	mch := &operatorsv1.MultiClusterHub{
 		ObjectMeta: metav1.ObjectMeta{Namespace: "test"},
@@ -90,6 +148,27 @@ func TestValidateDeployment(t *testing.T) {
 	dep5 := dep.DeepCopy()
 	dep5.Spec.Template.Spec.Tolerations = nil
 
+	// 7. OpenShift image trigger on the component container
+	dep6 := dep.DeepCopy()
+	dep6.Annotations = map[string]string{
+		"deployment.kubernetes.io/revision": "1",
+		utils.AnnotationImageTriggers:       `[{"from":{"kind":"ImageStreamTag","name":"repo:latest"},"fieldPath":"spec.template.spec.containers[?(@.name==\"multiclusterhub-repo\")].image"}]`,
+	}
+	want6 := dep.DeepCopy()
+	want6.Annotations = map[string]string{"deployment.kubernetes.io/revision": "1"}
+
+	// 8. Tolerations added to the CR spec
+	mch7 := mch.DeepCopy()
+	mch7.Spec.Tolerations = []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "hub", Effect: corev1.TaintEffectNoSchedule}}
+	want7 := Deployment(mch7, ovr)
+
+	// 9. Persistence added to the CR spec
+	mch8 := mch.DeepCopy()
+	mch8.Spec.Overrides = &operatorsv1.Overrides{Persistence: &operatorsv1.PersistenceConfig{}}
+	want8 := Deployment(mch8, ovr)
+	want9 := dep.DeepCopy()
+	want9.Spec.Strategy.Type = appsv1.RollingUpdateDeploymentStrategyType
+
 	type args struct {
 		m   *operatorsv1.MultiClusterHub
 		dep *appsv1.Deployment
@@ -136,10 +215,34 @@ func TestValidateDeployment(t *testing.T) {
 			want:  dep,
 			want1: true,
 		},
+		{
+			name:  "Image trigger annotation",
+			args:  args{mch, dep6},
+			want:  want6,
+			want1: true,
+		},
+		{
+			name:  "Spec Tolerations",
+			args:  args{mch7, dep.DeepCopy()},
+			want:  want7,
+			want1: true,
+		},
+		{
+			name:  "Persistence",
+			args:  args{mch8, dep.DeepCopy()},
+			want:  want8,
+			want1: true,
+		},
+		{
+			name:  "Persistence removed",
+			args:  args{mch, want8.DeepCopy()},
+			want:  want9,
+			want1: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, got1 := ValidateDeployment(tt.args.m, ovr, dep, tt.args.dep)
+			got, got1 := ValidateDeployment(tt.args.m, ovr, Deployment(tt.args.m, ovr), tt.args.dep)
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("ValidateDeployment() got = %v, want %v", got, tt.want)
 			}
@@ -0,0 +1,108 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package helmrepo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// IndexConfigMapName is the name of the configmap recording the charts served by the helm repo
+var IndexConfigMapName = "multiclusterhub-repo-index"
+
+// IndexTimeout bounds requests for the helm repo chart index
+var IndexTimeout = 5 * time.Second
+
+// IndexRefreshPeriod is how often the recorded chart index is refreshed
+var IndexRefreshPeriod = 10 * time.Minute
+
+// AnnotationIndexRefreshed sits in the index configmap's annotations to identify when the index was last read
+var AnnotationIndexRefreshed = "installer.open-cluster-management.io/index-refreshed"
+
+// chartIndex is the subset of a helm repository index.yaml used to list charts
+type chartIndex struct {
+	Entries map[string][]struct {
+		Version string `json:"version"`
+	} `json:"entries"`
+}
+
+// IndexURL returns the in-cluster address of the helm repo chart index
+func IndexURL(m *operatorsv1.MultiClusterHub) string {
+	return fmt.Sprintf("http://%s.%s.svc.cluster.local:%d/charts/index.yaml", HelmRepoName, m.Namespace, Port)
+}
+
+// ChartIndex reads a helm repository index and returns the versions available for each chart
+func ChartIndex(url string) (map[string][]string, error) {
+	client := &http.Client{Timeout: IndexTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status reading chart index: %s", resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	index := &chartIndex{}
+	if err := yaml.Unmarshal(body, index); err != nil {
+		return nil, err
+	}
+
+	charts := make(map[string][]string)
+	for name, entries := range index.Entries {
+		for _, e := range entries {
+			charts[name] = append(charts[name], e.Version)
+		}
+		sort.Strings(charts[name])
+	}
+	return charts, nil
+}
+
+// IndexConfigMap returns a configmap listing the versions available for each chart
+func IndexConfigMap(m *operatorsv1.MultiClusterHub, charts map[string][]string, refreshed time.Time) *corev1.ConfigMap {
+	data := make(map[string]string)
+	for name, versions := range charts {
+		data[name] = strings.Join(versions, ",")
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      IndexConfigMapName,
+			Namespace: m.Namespace,
+			Labels:    labels(),
+			Annotations: map[string]string{
+				AnnotationIndexRefreshed: refreshed.UTC().Format(time.RFC3339),
+			},
+		},
+		Data: data,
+	}
+	cm.SetOwnerReferences([]metav1.OwnerReference{
+		*metav1.NewControllerRef(m, m.GetObjectKind().GroupVersionKind()),
+	})
+	return cm
+}
+
+// IndexNeedsRefresh returns true if the recorded chart index is older than the refresh period
+func IndexNeedsRefresh(cm *corev1.ConfigMap, now time.Time) bool {
+	refreshed, err := time.Parse(time.RFC3339, cm.GetAnnotations()[AnnotationIndexRefreshed])
+	if err != nil {
+		return true
+	}
+	return now.Sub(refreshed) >= IndexRefreshPeriod
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package helmrepo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testIndex = `apiVersion: v1
+entries:
+  console-chart:
+  - name: console-chart
+    version: 2.2.1
+  - name: console-chart
+    version: 2.2.0
+  grc:
+  - name: grc
+    version: 2.2.0
+`
+
+func TestChartIndex(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/charts/index.yaml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(testIndex))
+	}))
+	defer server.Close()
+
+	t.Run("Read index", func(t *testing.T) {
+		got, err := ChartIndex(server.URL + "/charts/index.yaml")
+		if err != nil {
+			t.Fatalf("ChartIndex() error = %v", err)
+		}
+		want := map[string][]string{
+			"console-chart": {"2.2.0", "2.2.1"},
+			"grc":           {"2.2.0"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ChartIndex() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Missing index", func(t *testing.T) {
+		if _, err := ChartIndex(server.URL + "/missing"); err == nil {
+			t.Errorf("ChartIndex() expected error for missing index")
+		}
+	})
+}
+
+func TestIndexNeedsRefresh(t *testing.T) {
+	mch := &operatorsv1.MultiClusterHub{ObjectMeta: metav1.ObjectMeta{Namespace: "testNS"}}
+	now := time.Now()
+	cm := IndexConfigMap(mch, map[string][]string{"grc": {"2.2.0"}}, now)
+
+	if cm.Data["grc"] != "2.2.0" {
+		t.Errorf("IndexConfigMap() data = %v, want grc=2.2.0", cm.Data)
+	}
+	if IndexNeedsRefresh(cm, now.Add(time.Minute)) {
+		t.Errorf("IndexNeedsRefresh() = true for a recent index")
+	}
+	if !IndexNeedsRefresh(cm, now.Add(IndexRefreshPeriod)) {
+		t.Errorf("IndexNeedsRefresh() = false for an expired index")
+	}
+}
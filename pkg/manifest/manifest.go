@@ -16,6 +16,7 @@ import (
 	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/utils"
 	"github.com/open-cluster-management/multicloudhub-operator/version"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -95,6 +96,10 @@ func buildFullImageReference(mch *operatorsv1.MultiClusterHub, mi ManifestImage)
 
 	switch imageFormat := GetImageOverrideType(mch); imageFormat {
 	case Suffix:
+		// The image tag suffix override takes precedence over the annotation and applies to every tag
+		if suffix := utils.GetImageTagSuffix(mch); suffix != "" {
+			return fmt.Sprintf("%s/%s:%s-%s", registry, mi.ImageName, mi.ImageVersion, suffix)
+		}
 		suffix := utils.GetImageSuffix(mch)
 		return suffixFormat(mi, registry, suffix)
 	case Manifest:
@@ -121,6 +126,41 @@ func suffixFormat(mi ManifestImage, registry string, suffix string) string {
 	return fmt.Sprintf("%s/%s:%s-%s", registry, image, version, suffix)
 }
 
+// GetPrerequisites reads the API group versions each component subscription requires from the
+// versioned prerequisites manifest. No component has prerequisites if the manifest does not exist.
+func GetPrerequisites() (map[string][]schema.GroupVersion, error) {
+	manifestsPath, found := os.LookupEnv(ManifestsPathEnvVar)
+	if !found {
+		return nil, nil
+	}
+
+	filePath := path.Join(manifestsPath, version.Version+"-prerequisites.json")
+	contents, err := ioutil.ReadFile(filepath.Clean(filePath)) // #nosec G304 (filepath cleaned)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		log.Error(err, "Failed to read prerequisites manifest", "Path", filePath)
+		return nil, err
+	}
+
+	var manifestPrerequisites map[string][]string
+	if err := json.Unmarshal(contents, &manifestPrerequisites); err != nil {
+		return nil, err
+	}
+
+	prerequisites := make(map[string][]schema.GroupVersion)
+	for component, gvs := range manifestPrerequisites {
+		for _, s := range gvs {
+			gv, err := schema.ParseGroupVersion(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid prerequisite of %s: %w", component, err)
+			}
+			prerequisites[component] = append(prerequisites[component], gv)
+		}
+	}
+	return prerequisites, nil
+}
+
 // readManifestFile returns the byte content of a versioned image manifest file
 func readManifestFile(version string) ([]byte, error) {
 	manifestsPath, found := os.LookupEnv(ManifestsPathEnvVar)
@@ -5,11 +5,16 @@
 package manifest
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
 	"github.com/open-cluster-management/multicloudhub-operator/pkg/utils"
+	"github.com/open-cluster-management/multicloudhub-operator/version"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func TestGetImageOverrideType(t *testing.T) {
@@ -59,6 +64,50 @@ func Test_readManifestFile(t *testing.T) {
 	})
 }
 
+func TestGetPrerequisites(t *testing.T) {
+	dir, err := ioutil.TempDir("", "manifests")
+	if err != nil {
+		t.Fatalf("Failed to create manifests directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	os.Setenv(ManifestsPathEnvVar, dir)
+	defer os.Unsetenv(ManifestsPathEnvVar)
+	file := filepath.Join(dir, version.Version+"-prerequisites.json")
+
+	t.Run("No manifest", func(t *testing.T) {
+		got, err := GetPrerequisites()
+		if err != nil || got != nil {
+			t.Errorf("GetPrerequisites() = %v, %v, want nil, nil", got, err)
+		}
+	})
+
+	t.Run("Manifest", func(t *testing.T) {
+		content := `{"grc-sub": ["cluster.open-cluster-management.io/v1alpha1", "policy.open-cluster-management.io/v1"]}`
+		if err := ioutil.WriteFile(file, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write prerequisites manifest: %v", err)
+		}
+		want := map[string][]schema.GroupVersion{
+			"grc-sub": {
+				{Group: "cluster.open-cluster-management.io", Version: "v1alpha1"},
+				{Group: "policy.open-cluster-management.io", Version: "v1"},
+			},
+		}
+		got, err := GetPrerequisites()
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("GetPrerequisites() = %v, %v, want %v", got, err, want)
+		}
+	})
+
+	t.Run("Invalid group version", func(t *testing.T) {
+		if err := ioutil.WriteFile(file, []byte(`{"grc-sub": ["a/b/c"]}`), 0600); err != nil {
+			t.Fatalf("Failed to write prerequisites manifest: %v", err)
+		}
+		if _, err := GetPrerequisites(); err == nil {
+			t.Errorf("GetPrerequisites() did not return error")
+		}
+	})
+}
+
 func Test_buildFullImageReference(t *testing.T) {
 	mi := ManifestImage{
 		ImageKey:     "test_app",
@@ -67,6 +116,8 @@ func Test_buildFullImageReference(t *testing.T) {
 		ImageRemote:  "quay.io/open-cluster-management",
 		ImageDigest:  "sha256:abc123",
 	}
+	oauthProxy := mi
+	oauthProxy.ImageKey = "oauth_proxy"
 	mch := &operatorsv1.MultiClusterHub{}
 
 	mch1 := mch.DeepCopy()
@@ -77,6 +128,12 @@ func Test_buildFullImageReference(t *testing.T) {
 	mch3 := mch.DeepCopy()
 	mch3.SetAnnotations(map[string]string{utils.AnnotationSuffix: "baz"})
 
+	mch4 := mch.DeepCopy()
+	mch4.Spec.Overrides = &operatorsv1.Overrides{ImageTagSuffix: "debug"}
+
+	mch5 := mch3.DeepCopy()
+	mch5.Spec.Overrides = &operatorsv1.Overrides{ImageTagSuffix: "debug"}
+
 	type args struct {
 		mch *operatorsv1.MultiClusterHub
 		mi  ManifestImage
@@ -101,6 +158,26 @@ func Test_buildFullImageReference(t *testing.T) {
 			args: args{mch3, mi},
 			want: "quay.io/open-cluster-management/test-app:2.3.0-baz",
 		},
+		{
+			name: "Override suffix leaves digest pinned",
+			args: args{mch4, mi},
+			want: "quay.io/open-cluster-management/test-app@sha256:abc123",
+		},
+		{
+			name: "Override suffix takes precedence over annotation",
+			args: args{mch5, mi},
+			want: "quay.io/open-cluster-management/test-app:2.3.0-debug",
+		},
+		{
+			name: "Annotation suffix skips oauth proxy",
+			args: args{mch3, oauthProxy},
+			want: "quay.io/open-cluster-management/test-app:2.3.0",
+		},
+		{
+			name: "Override suffix applies to oauth proxy tag",
+			args: args{mch5, oauthProxy},
+			want: "quay.io/open-cluster-management/test-app:2.3.0-debug",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,31 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// Package metrics defines the Prometheus metrics exported by the multiclusterhub operator
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// DriftCorrections counts updates made to bring a managed resource back to its desired state
+	DriftCorrections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "drift_corrections_total",
+			Help: "Number of times the operator corrected drift in a managed resource",
+		},
+		[]string{"kind", "component"},
+	)
+)
+
+func init() {
+	// Served by the manager's metrics endpoint
+	metrics.Registry.MustRegister(DriftCorrections)
+}
+
+// RecordDriftCorrection increments the drift correction count for a managed resource
+func RecordDriftCorrection(kind, component string) {
+	DriftCorrections.WithLabelValues(kind, component).Inc()
+}
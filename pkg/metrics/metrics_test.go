@@ -0,0 +1,25 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRecordDriftCorrection(t *testing.T) {
+	before := testutil.ToFloat64(DriftCorrections.WithLabelValues("Deployment", "ocm-webhook"))
+
+	RecordDriftCorrection("Deployment", "ocm-webhook")
+	RecordDriftCorrection("Deployment", "ocm-webhook")
+	RecordDriftCorrection("Subscription", "search-prod-sub")
+
+	if got := testutil.ToFloat64(DriftCorrections.WithLabelValues("Deployment", "ocm-webhook")); got != before+2 {
+		t.Errorf("drift_corrections_total{kind=Deployment,component=ocm-webhook} = %v, want %v", got, before+2)
+	}
+	if got := testutil.ToFloat64(DriftCorrections.WithLabelValues("Subscription", "search-prod-sub")); got != 1 {
+		t.Errorf("drift_corrections_total{kind=Subscription,component=search-prod-sub} = %v, want 1", got)
+	}
+}
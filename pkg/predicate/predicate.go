@@ -17,7 +17,8 @@ var log = logf.Log.WithName("predicate")
 // GenerationChangedPredicate will skip update events that have no change in the object's metadata.generation field.
 // The metadata.generation field of an object is incremented by the API server when writes are made to the spec field of an object.
 // This allows a controller to ignore update events where the spec is unchanged, and only the metadata and/or status fields are changed.
-// This predicate is customized to not ignore certain annotations significant to the multiclusterhub reconciler.
+// This predicate is customized to not ignore certain annotations significant to the multiclusterhub reconciler,
+// changes to deletion state and finalizers, or periodic resyncs from the informer cache.
 type GenerationChangedPredicate struct {
 	predicate.Funcs
 }
@@ -46,6 +47,21 @@ func (GenerationChangedPredicate) Update(e event.UpdateEvent) bool {
 		return true
 	}
 
+	// Resync events from the informer carry an unchanged object and are let through to correct drift
+	if rv := e.MetaNew.GetResourceVersion(); rv != "" && rv == e.MetaOld.GetResourceVersion() {
+		return true
+	}
+
+	if (e.MetaOld.GetDeletionTimestamp() == nil) != (e.MetaNew.GetDeletionTimestamp() == nil) {
+		log.Info("Metadata deletion timestamp has changed")
+		return true
+	}
+
+	if !stringSetsMatch(e.MetaOld.GetFinalizers(), e.MetaNew.GetFinalizers()) {
+		log.Info("Metadata finalizers have changed")
+		return true
+	}
+
 	return e.MetaNew.GetGeneration() != e.MetaOld.GetGeneration()
 }
 
@@ -91,3 +107,21 @@ func hasInstallerLabels(labels map[string]string) bool {
 	_, namespaceExists := labels["installer.namespace"]
 	return nameExists && namespaceExists
 }
+
+// stringSetsMatch returns true if both lists contain the same elements, regardless of order
+func stringSetsMatch(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]int, len(a))
+	for _, v := range a {
+		set[v]++
+	}
+	for _, v := range b {
+		if set[v] == 0 {
+			return false
+		}
+		set[v]--
+	}
+	return true
+}
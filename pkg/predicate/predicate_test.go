@@ -140,6 +140,77 @@ func TestGenerationChangedPredicate(t *testing.T) {
 			t.Errorf("GenerationChangedPredicate.Update() = %v, want %v", got, want)
 		}
 	})
+
+	statusPodOld := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "biz", Name: "baz", Generation: 1, ResourceVersion: "1"},
+	}
+	statusPodNew := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "biz", Name: "baz", Generation: 1, ResourceVersion: "2", Labels: map[string]string{"foo": "bar"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	t.Run("Update event - status and labels changed", func(t *testing.T) {
+		e := event.UpdateEvent{
+			ObjectOld: statusPodOld,
+			MetaOld:   statusPodOld.GetObjectMeta(),
+			ObjectNew: statusPodNew,
+			MetaNew:   statusPodNew.GetObjectMeta(),
+		}
+		want := false
+		if got := pred.Update(e); got != want {
+			t.Errorf("GenerationChangedPredicate.Update() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Update event - resync", func(t *testing.T) {
+		e := event.UpdateEvent{
+			ObjectOld: statusPodOld,
+			MetaOld:   statusPodOld.GetObjectMeta(),
+			ObjectNew: statusPodOld,
+			MetaNew:   statusPodOld.GetObjectMeta(),
+		}
+		want := true
+		if got := pred.Update(e); got != want {
+			t.Errorf("GenerationChangedPredicate.Update() = %v, want %v", got, want)
+		}
+	})
+
+	now := metav1.Now()
+	deletedPod := statusPodNew.DeepCopy()
+	deletedPod.SetDeletionTimestamp(&now)
+	t.Run("Update event - deletion timestamp set", func(t *testing.T) {
+		e := event.UpdateEvent{
+			ObjectOld: statusPodOld,
+			MetaOld:   statusPodOld.GetObjectMeta(),
+			ObjectNew: deletedPod,
+			MetaNew:   deletedPod.GetObjectMeta(),
+		}
+		want := true
+		if got := pred.Update(e); got != want {
+			t.Errorf("GenerationChangedPredicate.Update() = %v, want %v", got, want)
+		}
+	})
+
+	finalizedPod := statusPodNew.DeepCopy()
+	finalizedPod.SetFinalizers([]string{"foo"})
+	t.Run("Update event - finalizers changed", func(t *testing.T) {
+		e := event.UpdateEvent{
+			ObjectOld: statusPodOld,
+			MetaOld:   statusPodOld.GetObjectMeta(),
+			ObjectNew: finalizedPod,
+			MetaNew:   finalizedPod.GetObjectMeta(),
+		}
+		want := true
+		if got := pred.Update(e); got != want {
+			t.Errorf("GenerationChangedPredicate.Update() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Delete event", func(t *testing.T) {
+		want := true
+		if got := pred.Delete(deleteEvent(pod)); got != want {
+			t.Errorf("GenerationChangedPredicate.Delete() = %v, want %v", got, want)
+		}
+	})
 }
 
 func TestInstallerLabelPredicate(t *testing.T) {
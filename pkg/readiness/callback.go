@@ -0,0 +1,72 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package readiness
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	// CallbackAttempts is how many times delivery of the ready callback is attempted
+	CallbackAttempts = 5
+	// CallbackRetryPeriod is how long to wait between ready callback attempts
+	CallbackRetryPeriod = 30 * time.Second
+	// CallbackTimeout bounds each ready callback request
+	CallbackTimeout = 10 * time.Second
+)
+
+// Callback is the body posted to the ready callback URL
+type Callback struct {
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace"`
+	Version    string `json:"version"`
+	ConfigHash string `json:"configHash"`
+}
+
+// NotifyReady posts the callback to url in the background so the caller is never blocked
+func NotifyReady(url string, cb Callback) {
+	go func() {
+		if err := SendCallback(url, cb); err != nil {
+			log.Error(err, "Giving up on ready callback", "URL", url, "Attempts", CallbackAttempts)
+		}
+	}()
+}
+
+// SendCallback posts the callback to url, retrying up to CallbackAttempts times until a 2xx response is received
+func SendCallback(url string, cb Callback) error {
+	body, err := json.Marshal(cb)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: CallbackTimeout}
+	for attempt := 1; ; attempt++ {
+		err = post(client, url, body)
+		if err == nil {
+			log.Info("Delivered ready callback", "URL", url)
+			return nil
+		}
+		if attempt >= CallbackAttempts {
+			return err
+		}
+		log.Info("Ready callback failed, retrying", "URL", url, "Attempt", attempt, "Error", err.Error())
+		time.Sleep(CallbackRetryPeriod)
+	}
+}
+
+func post(client *http.Client, url string, body []byte) error {
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return nil
+}
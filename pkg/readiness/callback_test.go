@@ -0,0 +1,52 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package readiness
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSendCallback(t *testing.T) {
+	CallbackRetryPeriod = time.Millisecond
+	want := Callback{Name: "multiclusterhub", Namespace: "open-cluster-management", Version: "2.2.0", ConfigHash: "abc"}
+
+	tests := []struct {
+		name      string
+		failures  int
+		wantCalls int
+		wantErr   bool
+	}{
+		{name: "Delivered first time", failures: 0, wantCalls: 1},
+		{name: "Delivered after retries", failures: 2, wantCalls: 3},
+		{name: "Attempts exhausted", failures: CallbackAttempts, wantCalls: CallbackAttempts, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				var got Callback
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil || got != want {
+					t.Errorf("Callback body = %+v, want %+v", got, want)
+				}
+				if calls <= tt.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			defer server.Close()
+
+			err := SendCallback(server.URL, want)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SendCallback() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("SendCallback() made %d calls, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// Package readiness publishes the operator's readiness to a file for file-based health checks
+package readiness
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+var log = logf.Log.WithName("readiness")
+
+// FileEnvVar names the environment variable holding the path of the readiness file. No file is written if unset.
+const FileEnvVar = "READINESS_FILE"
+
+// WritePeriod is how often the readiness file is rewritten
+var WritePeriod = 10 * time.Second
+
+const (
+	// Ready is reported once the multiclusterhub is running
+	Ready = "ready"
+	// NotReady is reported until the multiclusterhub is running
+	NotReady = "notready"
+)
+
+// Result is the content of the readiness file
+type Result struct {
+	Status    string    `json:"status"`
+	Phase     string    `json:"phase"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+var (
+	mu    sync.Mutex
+	phase operatorsv1.HubPhaseType
+)
+
+// SetPhase records the latest multiclusterhub phase observed by the reconciler
+func SetPhase(p operatorsv1.HubPhaseType) {
+	mu.Lock()
+	defer mu.Unlock()
+	phase = p
+}
+
+// Current returns the readiness derived from the latest recorded phase
+func Current() Result {
+	mu.Lock()
+	defer mu.Unlock()
+	r := Result{Status: NotReady, Phase: string(phase), Timestamp: time.Now().UTC()}
+	if phase == operatorsv1.HubRunning {
+		r.Status = Ready
+	}
+	return r
+}
+
+// WriteFile atomically writes the result to path, so readers never see a partial file
+func WriteFile(path string, r Result) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// FileWriter returns a runnable that rewrites the readiness file every WritePeriod until stopped
+func FileWriter(path string) manager.RunnableFunc {
+	return func(stop <-chan struct{}) error {
+		ticker := time.NewTicker(WritePeriod)
+		defer ticker.Stop()
+		for {
+			if err := WriteFile(path, Current()); err != nil {
+				log.Error(err, "Failed to write readiness file", "Path", path)
+			}
+			select {
+			case <-stop:
+				return nil
+			case <-ticker.C:
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package readiness
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
+)
+
+func readResult(t *testing.T, path string) Result {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read readiness file: %v", err)
+	}
+	r := Result{}
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatalf("Failed to parse readiness file: %v", err)
+	}
+	return r
+}
+
+func TestWriteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "readiness")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ready.json")
+
+	SetPhase(operatorsv1.HubInstalling)
+	if err := WriteFile(path, Current()); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if r := readResult(t, path); r.Status != NotReady || r.Phase != string(operatorsv1.HubInstalling) {
+		t.Errorf("WriteFile() wrote %+v, want %s with phase %s", r, NotReady, operatorsv1.HubInstalling)
+	}
+
+	SetPhase(operatorsv1.HubRunning)
+	if err := WriteFile(path, Current()); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if r := readResult(t, path); r.Status != Ready {
+		t.Errorf("WriteFile() wrote %+v, want %s", r, Ready)
+	}
+
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 1 {
+		t.Errorf("WriteFile() left %d files in the directory, want 1", len(files))
+	}
+}
+
+func TestFileWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "readiness")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ready.json")
+
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() { done <- FileWriter(path)(stop) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("FileWriter() did not write the readiness file")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	close(stop)
+	if err := <-done; err != nil {
+		t.Errorf("FileWriter() error = %v", err)
+	}
+}
@@ -0,0 +1,341 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// Package registry queries container image registries for image metadata
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Timeout bounds each request made to an image registry
+var Timeout = 5 * time.Second
+
+// CacheTTL is how long successful image query results are reused
+var CacheTTL = time.Hour
+
+// ErrorCacheTTL is how long a failed image query is reused before the registry is queried again
+var ErrorCacheTTL = time.Minute
+
+// scheme is the protocol used to reach registries
+var scheme = "https"
+
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+}
+
+// Reference is a parsed image reference
+type Reference struct {
+	Registry   string
+	Repository string
+	// Tag or digest of the image
+	Reference string
+}
+
+// ParseReference splits an image into its registry, repository and tag or digest
+func ParseReference(image string) Reference {
+	ref := Reference{Registry: "docker.io", Reference: "latest"}
+	name := strings.TrimSpace(image)
+
+	if i := strings.Index(name, "@"); i >= 0 {
+		ref.Reference = name[i+1:]
+		name = name[:i]
+	} else if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		ref.Reference = name[i+1:]
+		name = name[:i]
+	}
+
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		ref.Registry = parts[0]
+		name = parts[1]
+	}
+	if ref.Registry == "docker.io" && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	ref.Repository = name
+	return ref
+}
+
+// Credential authenticates requests to a registry
+type Credential struct {
+	Username string
+	Password string
+}
+
+// Keychain holds registry credentials keyed by registry host
+type Keychain map[string]Credential
+
+// KeychainFromDockerConfig reads the registry credentials of a .dockerconfigjson pull secret
+func KeychainFromDockerConfig(data []byte) (Keychain, error) {
+	cfg := struct {
+		Auths map[string]struct {
+			Auth     string `json:"auth"`
+			Username string `json:"username"`
+			Password string `json:"password"`
+		} `json:"auths"`
+	}{}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+
+	keychain := Keychain{}
+	for server, auth := range cfg.Auths {
+		cred := Credential{Username: auth.Username, Password: auth.Password}
+		if auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("invalid auth for registry %s: %w", server, err)
+			}
+			parts := strings.SplitN(string(decoded), ":", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("invalid auth for registry %s", server)
+			}
+			cred.Username, cred.Password = parts[0], parts[1]
+		}
+		keychain[registryHost(server)] = cred
+	}
+	return keychain, nil
+}
+
+// registryHost reduces a docker config server entry, which may be a URL, to the registry host it applies to
+func registryHost(server string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(server, "https://"), "http://")
+	if i := strings.Index(host, "/"); i >= 0 {
+		host = host[:i]
+	}
+	if host == "index.docker.io" || host == "registry-1.docker.io" {
+		return "docker.io"
+	}
+	return host
+}
+
+type manifest struct {
+	Manifests []struct {
+		Digest   string `json:"digest"`
+		Platform struct {
+			Architecture string `json:"architecture"`
+			OS           string `json:"os"`
+		} `json:"platform"`
+	} `json:"manifests"`
+	Config struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
+}
+
+type imageConfig struct {
+	Architecture string `json:"architecture"`
+}
+
+type cacheEntry struct {
+	architectures []string
+	err           error
+	expires       time.Time
+}
+
+var (
+	cacheMu sync.Mutex
+	cache   = map[string]cacheEntry{}
+)
+
+// Architectures returns the sorted CPU architectures an image is published for, authenticating with the
+// keychain credentials for its registry if there are any. Results are cached for CacheTTL and failures for
+// ErrorCacheTTL. Failures caused by the context ending are not cached.
+func Architectures(ctx context.Context, image string, keychain Keychain) ([]string, error) {
+	cacheMu.Lock()
+	entry, ok := cache[image]
+	cacheMu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.architectures, entry.err
+	}
+
+	ref := ParseReference(image)
+	var cred *Credential
+	if c, ok := keychain[ref.Registry]; ok {
+		cred = &c
+	}
+	archs, err := queryArchitectures(ctx, ref, cred)
+	if err != nil && ctx.Err() != nil {
+		return nil, err
+	}
+
+	ttl := CacheTTL
+	if err != nil {
+		ttl = ErrorCacheTTL
+	}
+	cacheMu.Lock()
+	cache[image] = cacheEntry{architectures: archs, err: err, expires: time.Now().Add(ttl)}
+	cacheMu.Unlock()
+	return archs, err
+}
+
+func queryArchitectures(ctx context.Context, ref Reference, cred *Credential) ([]string, error) {
+	c := &client{http: &http.Client{Timeout: Timeout}, ctx: ctx, ref: ref, cred: cred}
+
+	body, err := c.get(fmt.Sprintf("/v2/%s/manifests/%s", ref.Repository, ref.Reference), manifestMediaTypes)
+	if err != nil {
+		return nil, err
+	}
+	m := &manifest{}
+	if err := json.Unmarshal(body, m); err != nil {
+		return nil, err
+	}
+
+	set := map[string]bool{}
+	for _, entry := range m.Manifests {
+		if entry.Platform.Architecture != "" && entry.Platform.Architecture != "unknown" {
+			set[entry.Platform.Architecture] = true
+		}
+	}
+
+	// A single image manifest records its architecture in the image config
+	if len(m.Manifests) == 0 && m.Config.Digest != "" {
+		body, err := c.get(fmt.Sprintf("/v2/%s/blobs/%s", ref.Repository, m.Config.Digest), nil)
+		if err != nil {
+			return nil, err
+		}
+		cfg := &imageConfig{}
+		if err := json.Unmarshal(body, cfg); err != nil {
+			return nil, err
+		}
+		if cfg.Architecture != "" {
+			set[cfg.Architecture] = true
+		}
+	}
+
+	var archs []string
+	for a := range set {
+		archs = append(archs, a)
+	}
+	sort.Strings(archs)
+	return archs, nil
+}
+
+// client makes requests to a registry, authenticating when challenged. Requests are anonymous unless the
+// client has a credential.
+type client struct {
+	http *http.Client
+	ctx  context.Context
+	ref  Reference
+	cred *Credential
+	// authorization is the Authorization header negotiated with the registry
+	authorization string
+}
+
+func (c *client) get(path string, accept []string) ([]byte, error) {
+	resp, err := c.do(path, accept)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && c.authorization == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if err := c.authenticate(challenge); err != nil {
+			return nil, err
+		}
+		resp, err = c.do(path, accept)
+		if err != nil {
+			return nil, err
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry %s returned %s for %s", c.ref.Registry, resp.Status, path)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+func (c *client) do(path string, accept []string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, fmt.Sprintf("%s://%s%s", scheme, c.ref.Registry, path), nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range accept {
+		req.Header.Add("Accept", a)
+	}
+	if c.authorization != "" {
+		req.Header.Set("Authorization", c.authorization)
+	}
+	return c.http.Do(req)
+}
+
+// authenticate answers a basic challenge with the client credential, or requests a pull token from the realm
+// named in a bearer challenge
+func (c *client) authenticate(challenge string) error {
+	if strings.HasPrefix(strings.ToLower(challenge), "basic") {
+		if c.cred == nil {
+			return fmt.Errorf("registry %s requires credentials", c.ref.Registry)
+		}
+		auth := base64.StdEncoding.EncodeToString([]byte(c.cred.Username + ":" + c.cred.Password))
+		c.authorization = "Basic " + auth
+		return nil
+	}
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return fmt.Errorf("registry %s requires unsupported authentication", c.ref.Registry)
+	}
+
+	params := map[string]string{}
+	for _, p := range strings.Split(challenge[len("bearer "):], ",") {
+		kv := strings.SplitN(strings.TrimSpace(p), "=", 2)
+		if len(kv) == 2 {
+			params[kv[0]] = strings.Trim(kv[1], `"`)
+		}
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return fmt.Errorf("registry %s returned an invalid authentication realm", c.ref.Registry)
+	}
+	q := realm.Query()
+	if params["service"] != "" {
+		q.Set("service", params["service"])
+	}
+	q.Set("scope", fmt.Sprintf("repository:%s:pull", c.ref.Repository))
+	realm.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return err
+	}
+	if c.cred != nil {
+		req.SetBasicAuth(c.cred.Username, c.cred.Password)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry %s token request returned %s", c.ref.Registry, resp.Status)
+	}
+
+	t := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return err
+	}
+	token := t.Token
+	if token == "" {
+		token = t.AccessToken
+	}
+	if token == "" {
+		return fmt.Errorf("registry %s returned an empty token", c.ref.Registry)
+	}
+	c.authorization = "Bearer " + token
+	return nil
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		image string
+		want  Reference
+	}{
+		{
+			image: "quay.io/open-cluster-management/multicloud-manager@sha256:abc",
+			want:  Reference{Registry: "quay.io", Repository: "open-cluster-management/multicloud-manager", Reference: "sha256:abc"},
+		},
+		{
+			image: "localhost:5000/repo/image:2.2.0",
+			want:  Reference{Registry: "localhost:5000", Repository: "repo/image", Reference: "2.2.0"},
+		},
+		{
+			image: "busybox",
+			want:  Reference{Registry: "docker.io", Repository: "library/busybox", Reference: "latest"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			if got := ParseReference(tt.image); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseReference() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArchitectures(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			if user, pass, _ := r.BasicAuth(); strings.Contains(r.URL.Query().Get("scope"), "private") && (user != "user" || pass != "pass") {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"token": "abc"}`)
+		case r.Header.Get("Authorization") != "Bearer abc":
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/v2/multi/manifests/1.0":
+			fmt.Fprint(w, `{"manifests": [{"platform": {"architecture": "arm64"}}, {"platform": {"architecture": "amd64"}}]}`)
+		case r.URL.Path == "/v2/single/manifests/1.0":
+			fmt.Fprint(w, `{"config": {"digest": "sha256:cfg"}}`)
+		case r.URL.Path == "/v2/private/manifests/1.0":
+			fmt.Fprint(w, `{"manifests": [{"platform": {"architecture": "ppc64le"}}]}`)
+		case r.URL.Path == "/v2/single/blobs/sha256:cfg":
+			fmt.Fprint(w, `{"architecture": "s390x"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	scheme = "http"
+	defer func() { scheme = "https" }()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	keychain := Keychain{host: {Username: "user", Password: "pass"}}
+
+	tests := []struct {
+		image    string
+		keychain Keychain
+		want     []string
+		wantErr  bool
+	}{
+		{image: host + "/multi:1.0", want: []string{"amd64", "arm64"}},
+		{image: host + "/single:1.0", want: []string{"s390x"}},
+		{image: host + "/missing:1.0", wantErr: true},
+		{image: host + "/private:1.0", keychain: keychain, want: []string{"ppc64le"}},
+		{image: host + "/private:anonymous", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			got, err := Architectures(context.TODO(), tt.image, tt.keychain)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Architectures() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Architectures() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArchitecturesBasicAuth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "pass" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"manifests": [{"platform": {"architecture": "amd64"}}]}`)
+	}))
+	defer server.Close()
+
+	scheme = "http"
+	defer func() { scheme = "https" }()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	if _, err := Architectures(context.TODO(), host+"/basic:anonymous", nil); err == nil {
+		t.Errorf("Architectures() error = nil, want an error without credentials")
+	}
+	got, err := Architectures(context.TODO(), host+"/basic:1.0", Keychain{host: {Username: "user", Password: "pass"}})
+	if err != nil || !reflect.DeepEqual(got, []string{"amd64"}) {
+		t.Errorf("Architectures() = %v, %v, want [amd64]", got, err)
+	}
+}
+
+func TestArchitecturesCache(t *testing.T) {
+	requests := 0
+	available := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if !available {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"manifests": [{"platform": {"architecture": "amd64"}}]}`)
+	}))
+	defer server.Close()
+
+	scheme = "http"
+	defer func() { scheme = "https" }()
+	image := strings.TrimPrefix(server.URL, "http://") + "/cached:1.0"
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	if _, err := Architectures(ctx, image, nil); err == nil {
+		t.Fatalf("Architectures() error = nil, want an error for a cancelled context")
+	}
+
+	// A failure caused by the context ending is not cached
+	if _, err := Architectures(context.TODO(), image, nil); err == nil {
+		t.Fatalf("Architectures() error = nil, want an error while the registry is unavailable")
+	}
+	if requests != 1 {
+		t.Fatalf("Architectures() made %d requests, want 1", requests)
+	}
+
+	// A registry failure is reused until ErrorCacheTTL passes
+	available = true
+	if _, err := Architectures(context.TODO(), image, nil); err == nil || requests != 1 {
+		t.Errorf("Architectures() = %v after %d requests, want the cached failure", err, requests)
+	}
+
+	ttl := ErrorCacheTTL
+	ErrorCacheTTL = 0
+	defer func() { ErrorCacheTTL = ttl }()
+	cacheMu.Lock()
+	cache[image] = cacheEntry{err: fmt.Errorf("unavailable"), expires: time.Now()}
+	cacheMu.Unlock()
+	got, err := Architectures(context.TODO(), image, nil)
+	if err != nil || !reflect.DeepEqual(got, []string{"amd64"}) {
+		t.Errorf("Architectures() = %v, %v, want [amd64] once the failure expires", got, err)
+	}
+}
+
+func TestKeychainFromDockerConfig(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("user:pass"))
+	data := fmt.Sprintf(`{"auths": {"quay.io": {"auth": "%s"}, "https://index.docker.io/v1/": {"username": "hub", "password": "secret"}}}`, auth)
+
+	got, err := KeychainFromDockerConfig([]byte(data))
+	if err != nil {
+		t.Fatalf("KeychainFromDockerConfig() error = %v", err)
+	}
+	want := Keychain{
+		"quay.io":   {Username: "user", Password: "pass"},
+		"docker.io": {Username: "hub", Password: "secret"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("KeychainFromDockerConfig() = %v, want %v", got, want)
+	}
+
+	if _, err := KeychainFromDockerConfig([]byte(`{"auths": {"quay.io": {"auth": "not base64"}}}`)); err == nil {
+		t.Errorf("KeychainFromDockerConfig() error = nil, want an error for invalid auth")
+	}
+}
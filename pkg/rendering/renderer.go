@@ -234,7 +234,11 @@ func (r *Renderer) renderSecret(res *resource.Resource) (*unstructured.Unstructu
 		data[tlsCert] = base64.StdEncoding.EncodeToString([]byte(cert.Cert))
 		data[tlsKey] = base64.StdEncoding.EncodeToString([]byte(cert.Key))
 		return u, nil
-	case "ocm-webhook-secret":
+	case foundation.WebhookSecretName:
+		// The webhook serves an externally provided certificate instead
+		if utils.GetWebhookTLSSecret(r.cr) != "" {
+			return nil, nil
+		}
 		cn := "ocm-webhook." + r.cr.Namespace + ".svc"
 		ca, err := utils.GenerateSelfSignedCACert(cn)
 		if err != nil {
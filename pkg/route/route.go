@@ -0,0 +1,119 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package route
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
+)
+
+// RouteName is the name of the route exposing the hub console
+var RouteName = "multicloud-console"
+
+// ServiceName is the name of the service the console route targets
+var ServiceName = "management-ingress"
+
+// GroupVersion is the API group version of OpenShift routes
+var GroupVersion = schema.GroupVersion{Group: "route.openshift.io", Version: "v1"}
+
+// Enabled returns true if the multiclusterhub requests a console route
+func Enabled(m *operatorsv1.MultiClusterHub) bool {
+	return m.Spec.Ingress.Route != nil && m.Spec.Ingress.Route.Enabled
+}
+
+// Route returns an unstructured Route object exposing the hub console
+func Route(m *operatorsv1.MultiClusterHub) *unstructured.Unstructured {
+	termination := "passthrough"
+	insecurePolicy := "None"
+	host := ""
+	if rs := m.Spec.Ingress.Route; rs != nil {
+		if rs.Termination != "" {
+			termination = rs.Termination
+		}
+		if rs.InsecureEdgeTerminationPolicy != "" {
+			insecurePolicy = rs.InsecureEdgeTerminationPolicy
+		}
+		host = rs.Host
+	}
+
+	spec := map[string]interface{}{
+		"to": map[string]interface{}{
+			"kind":   "Service",
+			"name":   ServiceName,
+			"weight": int64(100),
+		},
+		"tls": map[string]interface{}{
+			"termination":                   termination,
+			"insecureEdgeTerminationPolicy": insecurePolicy,
+		},
+		"wildcardPolicy": "None",
+	}
+	if host != "" {
+		spec["host"] = host
+	}
+
+	r := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": GroupVersion.String(),
+			"kind":       "Route",
+			"metadata": map[string]interface{}{
+				"name":      RouteName,
+				"namespace": m.Namespace,
+			},
+			"spec": spec,
+		},
+	}
+	r.SetOwnerReferences([]metav1.OwnerReference{
+		*metav1.NewControllerRef(m, m.GetObjectKind().GroupVersionKind()),
+	})
+	return r
+}
+
+// Validate returns the found route updated with the desired spec, and whether an update is needed.
+// A host assigned by the cluster is kept when no host is requested.
+func Validate(found, desired *unstructured.Unstructured) (*unstructured.Unstructured, bool) {
+	updated := found.DeepCopy()
+	needsUpdate := false
+
+	for _, field := range []string{"to", "tls", "wildcardPolicy"} {
+		want, _, _ := unstructured.NestedFieldCopy(desired.Object, "spec", field)
+		have, _, _ := unstructured.NestedFieldNoCopy(found.Object, "spec", field)
+		if !contains(have, want) {
+			_ = unstructured.SetNestedField(updated.Object, want, "spec", field)
+			needsUpdate = true
+		}
+	}
+
+	if host, ok, _ := unstructured.NestedString(desired.Object, "spec", "host"); ok {
+		if current, _, _ := unstructured.NestedString(found.Object, "spec", "host"); current != host {
+			_ = unstructured.SetNestedField(updated.Object, host, "spec", "host")
+			needsUpdate = true
+		}
+	}
+
+	return updated, needsUpdate
+}
+
+// contains returns true if have matches want, ignoring map entries that are only present in have
+func contains(have, want interface{}) bool {
+	wantMap, ok := want.(map[string]interface{})
+	if !ok {
+		return reflect.DeepEqual(have, want)
+	}
+	haveMap, ok := have.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for k, v := range wantMap {
+		if !contains(haveMap[k], v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package route
+
+import (
+	"testing"
+
+	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRoute(t *testing.T) {
+	mch := &operatorsv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testNS"},
+		Spec: operatorsv1.MultiClusterHubSpec{
+			Ingress: operatorsv1.IngressSpec{
+				Route: &operatorsv1.RouteSpec{Enabled: true, Host: "console.example.com", Termination: "reencrypt"},
+			},
+		},
+	}
+
+	t.Run("Route overrides", func(t *testing.T) {
+		r := Route(mch)
+		if !Enabled(mch) {
+			t.Errorf("Enabled() = false, want true")
+		}
+		if host, _, _ := unstructured.NestedString(r.Object, "spec", "host"); host != "console.example.com" {
+			t.Errorf("Route() host = %v, want %v", host, "console.example.com")
+		}
+		if term, _, _ := unstructured.NestedString(r.Object, "spec", "tls", "termination"); term != "reencrypt" {
+			t.Errorf("Route() termination = %v, want %v", term, "reencrypt")
+		}
+		if svc, _, _ := unstructured.NestedString(r.Object, "spec", "to", "name"); svc != ServiceName {
+			t.Errorf("Route() service = %v, want %v", svc, ServiceName)
+		}
+	})
+
+	t.Run("Route defaults", func(t *testing.T) {
+		r := Route(&operatorsv1.MultiClusterHub{ObjectMeta: metav1.ObjectMeta{Namespace: "testNS"}})
+		if _, ok, _ := unstructured.NestedString(r.Object, "spec", "host"); ok {
+			t.Errorf("Route() set a host when none was requested")
+		}
+		if term, _, _ := unstructured.NestedString(r.Object, "spec", "tls", "termination"); term != "passthrough" {
+			t.Errorf("Route() termination = %v, want %v", term, "passthrough")
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	mch := &operatorsv1.MultiClusterHub{ObjectMeta: metav1.ObjectMeta{Namespace: "testNS"}}
+
+	t.Run("Generated host is kept", func(t *testing.T) {
+		found := Route(mch)
+		_ = unstructured.SetNestedField(found.Object, "generated.example.com", "spec", "host")
+		_ = unstructured.SetNestedField(found.Object, "", "spec", "tls", "certificate")
+		if _, needsUpdate := Validate(found, Route(mch)); needsUpdate {
+			t.Errorf("Validate() needsUpdate = true, want false")
+		}
+	})
+
+	t.Run("Modified route is restored", func(t *testing.T) {
+		found := Route(mch)
+		_ = unstructured.SetNestedField(found.Object, "edge", "spec", "tls", "termination")
+		updated, needsUpdate := Validate(found, Route(mch))
+		if !needsUpdate {
+			t.Fatalf("Validate() needsUpdate = false, want true")
+		}
+		if term, _, _ := unstructured.NestedString(updated.Object, "spec", "tls", "termination"); term != "passthrough" {
+			t.Errorf("Validate() termination = %v, want %v", term, "passthrough")
+		}
+	})
+
+	t.Run("Requested host is enforced", func(t *testing.T) {
+		m := mch.DeepCopy()
+		m.Spec.Ingress.Route = &operatorsv1.RouteSpec{Enabled: true, Host: "console.example.com"}
+		updated, needsUpdate := Validate(Route(mch), Route(m))
+		if !needsUpdate {
+			t.Fatalf("Validate() needsUpdate = false, want true")
+		}
+		if host, _, _ := unstructured.NestedString(updated.Object, "spec", "host"); host != "console.example.com" {
+			t.Errorf("Validate() host = %v, want %v", host, "console.example.com")
+		}
+	})
+}
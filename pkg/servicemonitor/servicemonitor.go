@@ -0,0 +1,154 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// Package servicemonitor builds Prometheus Operator ServiceMonitors that scrape hub component services
+package servicemonitor
+
+import (
+	"reflect"
+
+	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// MetricsPortName is the name of a service port dedicated to serving metrics
+const MetricsPortName = "metrics"
+
+// MetricsServiceLabel marks a metrics service with the name of the component service it was derived from
+const MetricsServiceLabel = "ocm-metrics-service"
+
+// GroupVersion is the API group version of Prometheus Operator ServiceMonitors
+var GroupVersion = schema.GroupVersion{Group: "monitoring.coreos.com", Version: "v1"}
+
+// Enabled returns true if the multiclusterhub requests ServiceMonitors for its components
+func Enabled(m *operatorsv1.MultiClusterHub) bool {
+	return m.Spec.Overrides != nil && m.Spec.Overrides.EnableServiceMonitors
+}
+
+// MetricsServicesEnabled returns true if the multiclusterhub requests dedicated metrics services for its components
+func MetricsServicesEnabled(m *operatorsv1.MultiClusterHub) bool {
+	return m.Spec.Overrides != nil && m.Spec.Overrides.EnableMetricsServices
+}
+
+// MetricsService returns a ClusterIP service exposing only the metrics port of a component service. It selects
+// the same pods and carries the component service labels plus MetricsServiceLabel. Returns nil if the component
+// service has no metrics port.
+func MetricsService(m *operatorsv1.MultiClusterHub, svc *corev1.Service) *corev1.Service {
+	p, ok := metricsPort(svc)
+	if !ok {
+		return nil
+	}
+
+	labels := map[string]string{MetricsServiceLabel: svc.Name}
+	for k, v := range svc.Labels {
+		labels[k] = v
+	}
+	selector := map[string]string{}
+	for k, v := range svc.Spec.Selector {
+		selector[k] = v
+	}
+
+	s := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      svc.Name + "-" + MetricsPortName,
+			Namespace: m.Namespace,
+			Labels:    labels,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: selector,
+			Ports: []corev1.ServicePort{{
+				Name:       MetricsPortName,
+				Protocol:   corev1.ProtocolTCP,
+				Port:       p.Port,
+				TargetPort: p.TargetPort,
+			}},
+			Type: corev1.ServiceTypeClusterIP,
+		},
+	}
+	s.SetOwnerReferences([]metav1.OwnerReference{
+		*metav1.NewControllerRef(m, m.GetObjectKind().GroupVersionKind()),
+	})
+	return s
+}
+
+// ServiceMonitor returns an unstructured ServiceMonitor scraping the metrics port of a component service, or nil
+// if the service has no metrics port
+func ServiceMonitor(m *operatorsv1.MultiClusterHub, svc *corev1.Service) *unstructured.Unstructured {
+	p, ok := metricsPort(svc)
+	if !ok {
+		return nil
+	}
+
+	matchLabels := map[string]interface{}{}
+	for k, v := range svc.Labels {
+		matchLabels[k] = v
+	}
+
+	sm := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": GroupVersion.String(),
+			"kind":       "ServiceMonitor",
+			"metadata": map[string]interface{}{
+				"name":      svc.Name,
+				"namespace": m.Namespace,
+			},
+			"spec": map[string]interface{}{
+				"endpoints": []interface{}{endpoint(p)},
+				"namespaceSelector": map[string]interface{}{
+					"matchNames": []interface{}{m.Namespace},
+				},
+				"selector": map[string]interface{}{
+					"matchLabels": matchLabels,
+				},
+			},
+		},
+	}
+	sm.SetOwnerReferences([]metav1.OwnerReference{
+		*metav1.NewControllerRef(m, m.GetObjectKind().GroupVersionKind()),
+	})
+	return sm
+}
+
+// Validate returns the found ServiceMonitor updated with the desired spec, and whether an update is needed
+func Validate(found, desired *unstructured.Unstructured) (*unstructured.Unstructured, bool) {
+	want, _, _ := unstructured.NestedFieldCopy(desired.Object, "spec")
+	have, _, _ := unstructured.NestedFieldNoCopy(found.Object, "spec")
+	if reflect.DeepEqual(have, want) {
+		return found, false
+	}
+
+	updated := found.DeepCopy()
+	_ = unstructured.SetNestedField(updated.Object, want, "spec")
+	return updated, true
+}
+
+// metricsPort returns the service port named for metrics, and whether the service has one
+func metricsPort(svc *corev1.Service) (corev1.ServicePort, bool) {
+	for _, p := range svc.Spec.Ports {
+		if p.Name == MetricsPortName {
+			return p, true
+		}
+	}
+	return corev1.ServicePort{}, false
+}
+
+// endpoint returns the ServiceMonitor endpoint scraping a service port. Secure ports are scraped over https
+// with the Prometheus service account token.
+func endpoint(p corev1.ServicePort) map[string]interface{} {
+	ep := map[string]interface{}{
+		"port":     p.Name,
+		"path":     "/metrics",
+		"interval": "30s",
+	}
+	if p.Port == 443 {
+		ep["scheme"] = "https"
+		ep["bearerTokenFile"] = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+		ep["tlsConfig"] = map[string]interface{}{
+			"insecureSkipVerify": true,
+		}
+	}
+	return ep
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package servicemonitor
+
+import (
+	"testing"
+
+	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestServiceMonitor(t *testing.T) {
+	mch := &operatorsv1.MultiClusterHub{ObjectMeta: metav1.ObjectMeta{Namespace: "testNS"}}
+
+	t.Run("No metrics port", func(t *testing.T) {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub-repo", Labels: map[string]string{"app": "multiclusterhub-repo"}},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 3000, TargetPort: intstr.FromInt(3000)}}},
+		}
+		if sm := ServiceMonitor(mch, svc); sm != nil {
+			t.Errorf("ServiceMonitor() = %v, want nil for a service without a metrics port", sm)
+		}
+	})
+
+	t.Run("Metrics port", func(t *testing.T) {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "ocm-proxyserver"},
+			Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{
+				{Name: "secure", Port: 443},
+				{Name: MetricsPortName, Port: 8080},
+			}},
+		}
+		sm := ServiceMonitor(mch, svc)
+		if sm.GetName() != svc.Name || sm.GetNamespace() != "testNS" {
+			t.Errorf("ServiceMonitor() = %s/%s, want testNS/%s", sm.GetNamespace(), sm.GetName(), svc.Name)
+		}
+		endpoints, _, _ := unstructured.NestedSlice(sm.Object, "spec", "endpoints")
+		ep := endpoints[0].(map[string]interface{})
+		if ep["port"] != MetricsPortName {
+			t.Errorf("ServiceMonitor() port = %v, want %v", ep["port"], MetricsPortName)
+		}
+		if _, ok := ep["scheme"]; ok {
+			t.Errorf("ServiceMonitor() set a scheme for an insecure port")
+		}
+	})
+}
+
+func TestMetricsService(t *testing.T) {
+	mch := &operatorsv1.MultiClusterHub{ObjectMeta: metav1.ObjectMeta{Namespace: "testNS"}}
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "ocm-proxyserver", Labels: map[string]string{"app": "ocm-proxyserver"}},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "ocm-proxyserver"},
+			Ports: []corev1.ServicePort{
+				{Name: "secure", Port: 443, TargetPort: intstr.FromInt(6443), NodePort: 30443},
+				{Name: MetricsPortName, Port: 8383, TargetPort: intstr.FromInt(8383), NodePort: 30383},
+			},
+			Type: corev1.ServiceTypeNodePort,
+		},
+	}
+
+	ms := MetricsService(mch, svc)
+	if ms.Name != "ocm-proxyserver-metrics" || ms.Namespace != "testNS" {
+		t.Errorf("MetricsService() = %s/%s, want testNS/ocm-proxyserver-metrics", ms.Namespace, ms.Name)
+	}
+	if ms.Spec.Type != corev1.ServiceTypeClusterIP {
+		t.Errorf("MetricsService() type = %v, want %v", ms.Spec.Type, corev1.ServiceTypeClusterIP)
+	}
+	if ms.Labels["app"] != "ocm-proxyserver" || ms.Labels[MetricsServiceLabel] != "ocm-proxyserver" {
+		t.Errorf("MetricsService() labels = %v, want component labels and %s", ms.Labels, MetricsServiceLabel)
+	}
+	if ms.Spec.Selector["app"] != "ocm-proxyserver" {
+		t.Errorf("MetricsService() selector = %v, want the component selector", ms.Spec.Selector)
+	}
+	if len(ms.Spec.Ports) != 1 {
+		t.Fatalf("MetricsService() has %d ports, want 1", len(ms.Spec.Ports))
+	}
+	p := ms.Spec.Ports[0]
+	if p.Name != MetricsPortName || p.Port != 8383 || p.TargetPort != intstr.FromInt(8383) || p.NodePort != 0 {
+		t.Errorf("MetricsService() port = %+v, want metrics port 8383 without a node port", p)
+	}
+
+	svc.Spec.Ports = svc.Spec.Ports[:1]
+	if ms := MetricsService(mch, svc); ms != nil {
+		t.Errorf("MetricsService() = %v, want nil for a service without a metrics port", ms)
+	}
+
+	svc.Labels["app"] = "changed"
+	if ms.Labels["app"] != "ocm-proxyserver" {
+		t.Errorf("MetricsService() shares labels with the component service")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	mch := &operatorsv1.MultiClusterHub{ObjectMeta: metav1.ObjectMeta{Namespace: "testNS"}}
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "ocm-webhook", Labels: map[string]string{"app": "ocm-webhook"}},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: MetricsPortName, Port: 8383}}},
+	}
+
+	if _, needsUpdate := Validate(ServiceMonitor(mch, svc), ServiceMonitor(mch, svc)); needsUpdate {
+		t.Errorf("Validate() needsUpdate = true, want false")
+	}
+
+	found := ServiceMonitor(mch, svc)
+	_ = unstructured.SetNestedField(found.Object, map[string]interface{}{"app": "other"}, "spec", "selector", "matchLabels")
+	updated, needsUpdate := Validate(found, ServiceMonitor(mch, svc))
+	if !needsUpdate {
+		t.Fatalf("Validate() needsUpdate = false, want true")
+	}
+	if app, _, _ := unstructured.NestedString(updated.Object, "spec", "selector", "matchLabels", "app"); app != "ocm-webhook" {
+		t.Errorf("Validate() selector app = %v, want %v", app, "ocm-webhook")
+	}
+}
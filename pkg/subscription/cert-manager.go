@@ -35,6 +35,7 @@ func CertManager(m *operatorsv1.MultiClusterHub, overrides map[string]string) *u
 			"hubconfig": map[string]interface{}{
 				"replicaCount": utils.DefaultReplicaCount(m),
 				"nodeSelector": m.Spec.NodeSelector,
+				"tolerations":  utils.GetTolerations(m),
 			},
 		},
 	}
@@ -67,6 +68,7 @@ func CertWebhook(m *operatorsv1.MultiClusterHub, overrides map[string]string) *u
 			"hubconfig": map[string]interface{}{
 				"replicaCount": utils.DefaultReplicaCount(m),
 				"nodeSelector": m.Spec.NodeSelector,
+				"tolerations":  utils.GetTolerations(m),
 			},
 		},
 	}
@@ -79,6 +81,7 @@ func CertWebhook(m *operatorsv1.MultiClusterHub, overrides map[string]string) *u
 		"hubconfig": map[string]interface{}{
 			"replicaCount": utils.DefaultReplicaCount(m),
 			"nodeSelector": m.Spec.NodeSelector,
+			"tolerations":  utils.GetTolerations(m),
 		},
 	}
 
@@ -111,6 +114,7 @@ func ConfigWatcher(m *operatorsv1.MultiClusterHub, overrides map[string]string)
 			"hubconfig": map[string]interface{}{
 				"replicaCount": utils.DefaultReplicaCount(m),
 				"nodeSelector": m.Spec.NodeSelector,
+				"tolerations":  utils.GetTolerations(m),
 			},
 		},
 	}
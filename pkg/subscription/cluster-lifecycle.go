@@ -19,6 +19,7 @@ func ClusterLifecycle(m *operatorsv1.MultiClusterHub, overrides map[string]strin
 			"hubconfig": map[string]interface{}{
 				"replicaCount": utils.DefaultReplicaCount(m),
 				"nodeSelector": m.Spec.NodeSelector,
+				"tolerations":  utils.GetTolerations(m),
 			},
 			"global": map[string]interface{}{
 				"pullPolicy":      utils.GetImagePullPolicy(m),
@@ -22,6 +22,7 @@ func Console(m *operatorsv1.MultiClusterHub, overrides map[string]string, ingres
 			"hubconfig": map[string]interface{}{
 				"replicaCount": utils.DefaultReplicaCount(m),
 				"nodeSelector": m.Spec.NodeSelector,
+				"tolerations":  utils.GetTolerations(m),
 				"name":         m.Name,
 				"namespace":    m.Namespace,
 			},
@@ -20,6 +20,7 @@ func GRC(m *operatorsv1.MultiClusterHub, overrides map[string]string) *unstructu
 			"hubconfig": map[string]interface{}{
 				"replicaCount": utils.DefaultReplicaCount(m),
 				"nodeSelector": m.Spec.NodeSelector,
+				"tolerations":  utils.GetTolerations(m),
 			},
 			"global": map[string]interface{}{
 				"imageOverrides": overrides,
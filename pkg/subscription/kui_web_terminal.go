@@ -24,6 +24,7 @@ func KUIWebTerminal(m *operatorsv1.MultiClusterHub, overrides map[string]string,
 			"hubconfig": map[string]interface{}{
 				"replicaCount": utils.DefaultReplicaCount(m),
 				"nodeSelector": m.Spec.NodeSelector,
+				"tolerations":  utils.GetTolerations(m),
 			},
 			"global": map[string]interface{}{
 				"imageOverrides": overrides,
@@ -39,7 +39,7 @@ func newSubscription(m *operatorsv1.MultiClusterHub, s *Subscription) *unstructu
 				"namespace": s.Namespace,
 			},
 			"spec": map[string]interface{}{
-				"channel": m.Namespace + "/" + channel.ChannelName,
+				"channel": channel.Reference(m),
 				"name":    s.Name,
 				"placement": map[string]interface{}{
 					"local": true,
@@ -108,6 +108,10 @@ func setCustomCA(m *operatorsv1.MultiClusterHub, sub *Subscription) {
 func imageSuffix(m *operatorsv1.MultiClusterHub) (s string) {
 	s = utils.GetImageSuffix(m)
 	if s != "" {
+		// Charts are only given a postfix for tagged images, where the override takes precedence
+		if override := utils.GetImageTagSuffix(m); override != "" {
+			s = override
+		}
 		s = "-" + s
 	}
 	return
@@ -153,3 +153,49 @@ func TestSubscriptions(t *testing.T) {
 		})
 	}
 }
+
+func TestSubscriptionTolerations(t *testing.T) {
+	mch := &operatorsv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test"},
+	}
+	ovr := map[string]string{}
+
+	tolerations := func(sub *unstructured.Unstructured) interface{} {
+		spec := sub.Object["spec"].(map[string]interface{})
+		values := spec["packageOverrides"].([]map[string]interface{})[0]["packageOverrides"].([]map[string]interface{})[0]["value"]
+		return values.(map[string]interface{})["hubconfig"].(map[string]interface{})["tolerations"]
+	}
+
+	if got := tolerations(GRC(mch, ovr)); !reflect.DeepEqual(got, []corev1.Toleration{}) {
+		t.Errorf("hubconfig.tolerations = %v, want empty list when unset", got)
+	}
+
+	mch.Spec.Tolerations = []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}}
+	if got := tolerations(GRC(mch, ovr)); !reflect.DeepEqual(got, mch.Spec.Tolerations) {
+		t.Errorf("hubconfig.tolerations = %v, want %v", got, mch.Spec.Tolerations)
+	}
+}
+
+func TestSubscriptionImageTagPostfix(t *testing.T) {
+	mch := &operatorsv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test"},
+		Spec: operatorsv1.MultiClusterHubSpec{
+			Overrides: &operatorsv1.Overrides{ImageTagSuffix: "debug"},
+		},
+	}
+	postfix := func() interface{} {
+		spec := ClusterLifecycle(mch, map[string]string{}).Object["spec"].(map[string]interface{})
+		values := spec["packageOverrides"].([]map[string]interface{})[0]["packageOverrides"].([]map[string]interface{})[0]["value"]
+		return values.(map[string]interface{})["global"].(map[string]interface{})["imageTagPostfix"]
+	}
+
+	// Digest-pinned images get no postfix
+	if got := postfix(); got != "" {
+		t.Errorf("global.imageTagPostfix = %v, want no postfix for digest-pinned images", got)
+	}
+
+	mch.SetAnnotations(map[string]string{utils.AnnotationSuffix: "SNAPSHOT"})
+	if got := postfix(); got != "-debug" {
+		t.Errorf("global.imageTagPostfix = %v, want -debug", got)
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// Package supportbundle gathers operator state into a configmap that can be attached to a support case
+package supportbundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+
+	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConfigMapName is the name of the configmap holding the support bundle
+	ConfigMapName = "multiclusterhub-support-bundle"
+
+	// AnnotationRequest sits in the bundle configmap's annotations to identify the request it was generated for
+	AnnotationRequest = "installer.open-cluster-management.io/support-bundle-request"
+
+	// MaxSize bounds the total size of the bundle data, leaving headroom below the configmap size limit
+	MaxSize = 900 * 1024
+
+	// MaxEvents bounds the number of events included in the bundle
+	MaxEvents = 200
+
+	// Redacted replaces the value of fields that may hold credentials
+	Redacted = "<redacted>"
+)
+
+// sensitiveKey matches field names whose values are redacted
+var sensitiveKey = regexp.MustCompile(`(?i)(password|passwd|secret|token|credential|private|key$|cert$)`)
+
+// Bundle is the operator state gathered for a support case
+type Bundle struct {
+	Version string
+	Hub     *operatorsv1.MultiClusterHub
+	Config  interface{}
+	Events  []corev1.Event
+}
+
+// Handled returns true if the bundle configmap was already generated for the request
+func Handled(cm *corev1.ConfigMap, request string) bool {
+	return cm.GetAnnotations()[AnnotationRequest] == request
+}
+
+// ConfigMap returns a configmap holding the bundle, with credentials redacted and its size bounded. The most
+// recent events are kept when the bundle would otherwise be too large.
+func ConfigMap(m *operatorsv1.MultiClusterHub, request string, b Bundle) (*corev1.ConfigMap, error) {
+	hub := b.Hub.DeepCopy()
+	hub.ManagedFields = nil
+
+	data := map[string]string{"version": b.Version}
+	for key, obj := range map[string]interface{}{
+		"multiclusterhub.json": hub,
+		"config.json":          b.Config,
+	} {
+		content, err := redactedJSON(obj)
+		if err != nil {
+			return nil, err
+		}
+		data[key] = content
+	}
+
+	size := 0
+	for _, v := range data {
+		size += len(v)
+	}
+	if size > MaxSize {
+		return nil, fmt.Errorf("support bundle is %d bytes, more than the %d byte limit", size, MaxSize)
+	}
+
+	events := recentEvents(b.Events)
+	content, err := redactedJSON(events)
+	if err != nil {
+		return nil, err
+	}
+	for len(events) > 0 && size+len(content) > MaxSize {
+		events = events[:len(events)/2]
+		if content, err = redactedJSON(events); err != nil {
+			return nil, err
+		}
+	}
+	data["events.json"] = content
+	if len(events) < len(b.Events) {
+		data["truncated"] = fmt.Sprintf("%d of %d events included", len(events), len(b.Events))
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ConfigMapName,
+			Namespace: m.Namespace,
+			Annotations: map[string]string{
+				AnnotationRequest: request,
+			},
+		},
+		Data: data,
+	}
+	cm.SetOwnerReferences([]metav1.OwnerReference{
+		*metav1.NewControllerRef(m, m.GetObjectKind().GroupVersionKind()),
+	})
+	return cm, nil
+}
+
+// recentEvents returns up to MaxEvents events, most recent first
+func recentEvents(events []corev1.Event) []corev1.Event {
+	sorted := append([]corev1.Event{}, events...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[j].LastTimestamp.Before(&sorted[i].LastTimestamp)
+	})
+	if len(sorted) > MaxEvents {
+		sorted = sorted[:MaxEvents]
+	}
+	return sorted
+}
+
+// redactedJSON returns the indented JSON for obj with the values of sensitive fields replaced
+func redactedJSON(obj interface{}) (string, error) {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return "", err
+	}
+	out, err := json.MarshalIndent(redact(generic), "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// redact replaces the values of sensitive fields in decoded JSON
+func redact(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if _, isString := val.(string); isString && sensitiveKey.MatchString(k) {
+				t[k] = Redacted
+				continue
+			}
+			t[k] = redact(val)
+		}
+	case []interface{}:
+		for i := range t {
+			t[i] = redact(t[i])
+		}
+	}
+	return v
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package supportbundle
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConfigMap(t *testing.T) {
+	mch := &operatorsv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "testNS"},
+		Spec:       operatorsv1.MultiClusterHubSpec{ImagePullSecret: "pull-secret"},
+	}
+	config := map[string]interface{}{
+		"ImageOverrides": map[string]string{"multiclusterhub_repo": "quay.io/open-cluster-management/multiclusterhub-repo:2.2.0"},
+		"RegistryToken":  "abc123",
+	}
+
+	t.Run("Contents", func(t *testing.T) {
+		cm, err := ConfigMap(mch, "1", Bundle{Version: "2.2.0", Hub: mch, Config: config})
+		if err != nil {
+			t.Fatalf("ConfigMap() error = %v", err)
+		}
+		if !Handled(cm, "1") || Handled(cm, "2") {
+			t.Errorf("Handled() does not match the bundle request")
+		}
+		if cm.Data["version"] != "2.2.0" {
+			t.Errorf("ConfigMap() version = %v, want %v", cm.Data["version"], "2.2.0")
+		}
+		if !strings.Contains(cm.Data["multiclusterhub.json"], `"name": "multiclusterhub"`) {
+			t.Errorf("ConfigMap() does not include the multiclusterhub")
+		}
+		if !strings.Contains(cm.Data["config.json"], "multiclusterhub-repo:2.2.0") {
+			t.Errorf("ConfigMap() does not include the resolved configuration")
+		}
+		if strings.Contains(cm.Data["config.json"], "abc123") {
+			t.Errorf("ConfigMap() did not redact a token")
+		}
+	})
+
+	t.Run("Events are bounded", func(t *testing.T) {
+		var events []corev1.Event
+		now := time.Now()
+		for i := 0; i < 2*MaxEvents; i++ {
+			events = append(events, corev1.Event{
+				ObjectMeta:    metav1.ObjectMeta{Name: fmt.Sprintf("event-%d", i)},
+				Message:       strings.Repeat("x", 4096),
+				LastTimestamp: metav1.NewTime(now.Add(time.Duration(i) * time.Second)),
+			})
+		}
+		cm, err := ConfigMap(mch, "1", Bundle{Version: "2.2.0", Hub: mch, Config: config, Events: events})
+		if err != nil {
+			t.Fatalf("ConfigMap() error = %v", err)
+		}
+		size := 0
+		for _, v := range cm.Data {
+			size += len(v)
+		}
+		if size > MaxSize {
+			t.Errorf("ConfigMap() size = %d, want at most %d", size, MaxSize)
+		}
+		if cm.Data["truncated"] == "" {
+			t.Errorf("ConfigMap() did not report truncated events")
+		}
+		if !strings.Contains(cm.Data["events.json"], fmt.Sprintf("event-%d", 2*MaxEvents-1)) {
+			t.Errorf("ConfigMap() did not keep the most recent event")
+		}
+	})
+}
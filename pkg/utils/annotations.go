@@ -4,6 +4,7 @@
 package utils
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -21,6 +22,14 @@ var (
 	AnnotationImageOverridesCM = "mch-imageOverridesCM"
 	// AnnotationConfiguration sits in a resource's annotations to identify the configuration last used to create it
 	AnnotationConfiguration = "installer.open-cluster-management.io/last-applied-configuration"
+	// AnnotationConfigHash sits in a pod template's annotations to identify the content of the configmaps and secrets it references
+	AnnotationConfigHash = "installer.open-cluster-management.io/config-hash"
+	// AnnotationSupportBundle sits in multiclusterhub annotations to request a support bundle. Changing its value requests a new bundle
+	AnnotationSupportBundle = "installer.open-cluster-management.io/support-bundle"
+	// AnnotationImageTriggers sits in an OpenShift workload's annotations to rewrite container images when an image stream changes
+	AnnotationImageTriggers = "image.openshift.io/triggers"
+	// AnnotationResume sits in multiclusterhub annotations to resume reconciling after the error budget is exhausted. Changing its value resumes
+	AnnotationResume = "installer.open-cluster-management.io/resume"
 )
 
 // IsPaused returns true if the multiclusterhub instance is labeled as paused, and false otherwise
@@ -42,7 +51,9 @@ func AnnotationsMatch(old, new map[string]string) bool {
 	return old[AnnotationMCHPause] == new[AnnotationMCHPause] &&
 		old[AnnotationImageRepo] == new[AnnotationImageRepo] &&
 		old[AnnotationSuffix] == new[AnnotationSuffix] &&
-		old[AnnotationImageOverridesCM] == new[AnnotationImageOverridesCM]
+		old[AnnotationImageOverridesCM] == new[AnnotationImageOverridesCM] &&
+		old[AnnotationSupportBundle] == new[AnnotationSupportBundle] &&
+		old[AnnotationResume] == new[AnnotationResume]
 }
 
 // getAnnotation returns the annotation value for a given key, or an empty string if not set
@@ -64,11 +75,21 @@ func GetImageSuffix(instance *operatorsv1.MultiClusterHub) string {
 	return getAnnotation(instance, AnnotationSuffix)
 }
 
+// GetResumeRequest returns the resume annotation, or an empty string if not set
+func GetResumeRequest(instance *operatorsv1.MultiClusterHub) string {
+	return getAnnotation(instance, AnnotationResume)
+}
+
 // GetImageOverridesConfigmap returns the images override configmap annotation, or an empty string if not set
 func GetImageOverridesConfigmap(instance *operatorsv1.MultiClusterHub) string {
 	return getAnnotation(instance, AnnotationImageOverridesCM)
 }
 
+// GetSupportBundleRequest returns the support bundle request annotation, or an empty string if not set
+func GetSupportBundleRequest(instance *operatorsv1.MultiClusterHub) string {
+	return getAnnotation(instance, AnnotationSupportBundle)
+}
+
 func OverrideImageRepository(imageOverrides map[string]string, imageRepo string) map[string]string {
 	for imageKey, imageRef := range imageOverrides {
 		image := strings.LastIndex(imageRef, "/")
@@ -76,3 +97,42 @@ func OverrideImageRepository(imageOverrides map[string]string, imageRepo string)
 	}
 	return imageOverrides
 }
+
+// RemoveImageTriggers removes OpenShift image triggers that rewrite container images from the annotations, keeping
+// any other triggers and annotations. An unparseable trigger annotation is removed entirely. Returns true if the
+// annotations were changed.
+func RemoveImageTriggers(annotations map[string]string) bool {
+	value, ok := annotations[AnnotationImageTriggers]
+	if !ok {
+		return false
+	}
+
+	var triggers []map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &triggers); err != nil {
+		delete(annotations, AnnotationImageTriggers)
+		return true
+	}
+
+	kept := []map[string]interface{}{}
+	for _, t := range triggers {
+		if fieldPath, _ := t["fieldPath"].(string); strings.Contains(fieldPath, "containers") {
+			continue
+		}
+		kept = append(kept, t)
+	}
+	if len(kept) == len(triggers) {
+		return false
+	}
+	if len(kept) == 0 {
+		delete(annotations, AnnotationImageTriggers)
+		return true
+	}
+
+	b, err := json.Marshal(kept)
+	if err != nil {
+		delete(annotations, AnnotationImageTriggers)
+		return true
+	}
+	annotations[AnnotationImageTriggers] = string(b)
+	return true
+}
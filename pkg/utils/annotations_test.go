@@ -123,3 +123,56 @@ func TestOverrideImageRepository(t *testing.T) {
 		}
 	}
 }
+
+func TestRemoveImageTriggers(t *testing.T) {
+	containerTrigger := `{"from":{"kind":"ImageStreamTag","name":"web:latest"},"fieldPath":"spec.template.spec.containers[?(@.name==\"web\")].image"}`
+	otherTrigger := `{"fieldPath":"metadata.annotations.image","from":{"kind":"ImageStreamTag","name":"web:latest"}}`
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        map[string]string
+		changed     bool
+	}{
+		{
+			name:        "No triggers",
+			annotations: map[string]string{"a": "b"},
+			want:        map[string]string{"a": "b"},
+			changed:     false,
+		},
+		{
+			name:        "Container trigger",
+			annotations: map[string]string{"a": "b", AnnotationImageTriggers: "[" + containerTrigger + "]"},
+			want:        map[string]string{"a": "b"},
+			changed:     true,
+		},
+		{
+			name:        "Other trigger kept",
+			annotations: map[string]string{AnnotationImageTriggers: "[" + containerTrigger + "," + otherTrigger + "]"},
+			want:        map[string]string{AnnotationImageTriggers: "[" + otherTrigger + "]"},
+			changed:     true,
+		},
+		{
+			name:        "Only other triggers",
+			annotations: map[string]string{AnnotationImageTriggers: "[" + otherTrigger + "]"},
+			want:        map[string]string{AnnotationImageTriggers: "[" + otherTrigger + "]"},
+			changed:     false,
+		},
+		{
+			name:        "Unparseable triggers",
+			annotations: map[string]string{AnnotationImageTriggers: "not json"},
+			want:        map[string]string{},
+			changed:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if changed := RemoveImageTriggers(tt.annotations); changed != tt.changed {
+				t.Errorf("RemoveImageTriggers() = %v, want %v", changed, tt.changed)
+			}
+			if !reflect.DeepEqual(tt.annotations, tt.want) {
+				t.Errorf("RemoveImageTriggers() annotations = %v, want %v", tt.annotations, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReferencedConfig returns the sorted names of the configmaps and secrets mounted or
+// consumed as environment by the pod template
+func ReferencedConfig(template *corev1.PodTemplateSpec) (configMaps []string, secrets []string) {
+	cms, scs := map[string]bool{}, map[string]bool{}
+
+	for _, v := range template.Spec.Volumes {
+		if v.ConfigMap != nil {
+			cms[v.ConfigMap.Name] = true
+		}
+		if v.Secret != nil {
+			scs[v.Secret.SecretName] = true
+		}
+		if v.Projected != nil {
+			for _, src := range v.Projected.Sources {
+				if src.ConfigMap != nil {
+					cms[src.ConfigMap.Name] = true
+				}
+				if src.Secret != nil {
+					scs[src.Secret.Name] = true
+				}
+			}
+		}
+	}
+
+	containers := append([]corev1.Container{}, template.Spec.InitContainers...)
+	containers = append(containers, template.Spec.Containers...)
+	for _, c := range containers {
+		for _, ef := range c.EnvFrom {
+			if ef.ConfigMapRef != nil {
+				cms[ef.ConfigMapRef.Name] = true
+			}
+			if ef.SecretRef != nil {
+				scs[ef.SecretRef.Name] = true
+			}
+		}
+		for _, e := range c.Env {
+			if e.ValueFrom == nil {
+				continue
+			}
+			if e.ValueFrom.ConfigMapKeyRef != nil {
+				cms[e.ValueFrom.ConfigMapKeyRef.Name] = true
+			}
+			if e.ValueFrom.SecretKeyRef != nil {
+				scs[e.ValueFrom.SecretKeyRef.Name] = true
+			}
+		}
+	}
+
+	return sortedKeys(cms), sortedKeys(scs)
+}
+
+// ConfigHash returns a hash of the content of the configmaps and secrets referenced by the pod template.
+// Referenced objects that do not exist yet contribute only their name, so the hash changes once they are created.
+func ConfigHash(client runtimeclient.Client, namespace string, template *corev1.PodTemplateSpec) (string, error) {
+	configMaps, secrets := ReferencedConfig(template)
+	h := sha256.New()
+
+	for _, name := range configMaps {
+		fmt.Fprintf(h, "configmap/%s\n", name)
+		cm := &corev1.ConfigMap{}
+		err := client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, cm)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		for _, k := range sortedKeys(cm.Data) {
+			fmt.Fprintf(h, "%s=%s\n", k, cm.Data[k])
+		}
+		for _, k := range sortedKeys(cm.BinaryData) {
+			fmt.Fprintf(h, "%s=%x\n", k, cm.BinaryData[k])
+		}
+	}
+
+	for _, name := range secrets {
+		fmt.Fprintf(h, "secret/%s\n", name)
+		s := &corev1.Secret{}
+		err := client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, s)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		for _, k := range sortedKeys(s.Data) {
+			fmt.Fprintf(h, "%s=%x\n", k, s.Data[k])
+		}
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// HubConfigHash returns a hash of the multiclusterhub spec and the image overrides resolved from it
+func HubConfigHash(m *operatorsv1.MultiClusterHub, imageOverrides map[string]string) (string, error) {
+	h := sha256.New()
+	if err := json.NewEncoder(h).Encode(m.Spec); err != nil {
+		return "", err
+	}
+	if err := json.NewEncoder(h).Encode(imageOverrides); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// sortedKeys returns the keys of a string-keyed map in sorted order
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch t := m.(type) {
+	case map[string]bool:
+		for k := range t {
+			keys = append(keys, k)
+		}
+	case map[string]string:
+		for k := range t {
+			keys = append(keys, k)
+		}
+	case map[string][]byte:
+		for k := range t {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
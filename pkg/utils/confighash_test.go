@@ -0,0 +1,126 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package utils
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func configTemplate() *corev1.PodTemplateSpec {
+	return &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{
+				{Name: "ca", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "ca-bundle"},
+				}}},
+				{Name: "certs", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "serving-cert"}}},
+			},
+			Containers: []corev1.Container{{
+				Name: "app",
+				EnvFrom: []corev1.EnvFromSource{
+					{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "app-env"}}},
+				},
+				Env: []corev1.EnvVar{
+					{Name: "LOG_LEVEL", ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"}, Key: "level",
+					}}},
+				},
+			}},
+		},
+	}
+}
+
+func TestReferencedConfig(t *testing.T) {
+	configMaps, secrets := ReferencedConfig(configTemplate())
+	if want := []string{"app-config", "ca-bundle"}; !reflect.DeepEqual(configMaps, want) {
+		t.Errorf("ReferencedConfig() configMaps = %v, want %v", configMaps, want)
+	}
+	if want := []string{"app-env", "serving-cert"}; !reflect.DeepEqual(secrets, want) {
+		t.Errorf("ReferencedConfig() secrets = %v, want %v", secrets, want)
+	}
+}
+
+func TestConfigHash(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "ca-bundle", Namespace: "test"},
+		Data:       map[string]string{"ca.crt": "first"},
+	}
+	fakeclient := fake.NewFakeClient(cm)
+
+	initial, err := ConfigHash(fakeclient, "test", configTemplate())
+	if err != nil {
+		t.Fatalf("ConfigHash() error = %v", err)
+	}
+
+	t.Run("Unchanged config", func(t *testing.T) {
+		got, err := ConfigHash(fakeclient, "test", configTemplate())
+		if err != nil {
+			t.Fatalf("ConfigHash() error = %v", err)
+		}
+		if got != initial {
+			t.Errorf("ConfigHash() = %v, want %v", got, initial)
+		}
+	})
+
+	t.Run("Changed configmap", func(t *testing.T) {
+		cm.Data["ca.crt"] = "second"
+		if err := fakeclient.Update(context.TODO(), cm); err != nil {
+			t.Fatalf("Failed to update configmap: %v", err)
+		}
+		got, err := ConfigHash(fakeclient, "test", configTemplate())
+		if err != nil {
+			t.Fatalf("ConfigHash() error = %v", err)
+		}
+		if got == initial {
+			t.Errorf("ConfigHash() did not change after configmap update")
+		}
+	})
+
+	t.Run("Created secret", func(t *testing.T) {
+		before, _ := ConfigHash(fakeclient, "test", configTemplate())
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "serving-cert", Namespace: "test"},
+			Data:       map[string][]byte{"tls.crt": []byte("cert")},
+		}
+		if err := fakeclient.Create(context.TODO(), secret); err != nil {
+			t.Fatalf("Failed to create secret: %v", err)
+		}
+		got, err := ConfigHash(fakeclient, "test", configTemplate())
+		if err != nil {
+			t.Fatalf("ConfigHash() error = %v", err)
+		}
+		if got == before {
+			t.Errorf("ConfigHash() did not change after secret creation")
+		}
+	})
+}
+
+func TestHubConfigHash(t *testing.T) {
+	m := &operatorsv1.MultiClusterHub{Spec: operatorsv1.MultiClusterHubSpec{ImagePullSecret: "pull-secret"}}
+	overrides := map[string]string{"registration": "quay.io/open-cluster-management/registration:2.2.0"}
+
+	base, err := HubConfigHash(m, overrides)
+	if err != nil {
+		t.Fatalf("HubConfigHash() error = %v", err)
+	}
+	if again, _ := HubConfigHash(m.DeepCopy(), map[string]string{"registration": "quay.io/open-cluster-management/registration:2.2.0"}); again != base {
+		t.Errorf("HubConfigHash() is not stable for an unchanged config")
+	}
+
+	changed := m.DeepCopy()
+	changed.Spec.ImagePullSecret = "other-secret"
+	if h, _ := HubConfigHash(changed, overrides); h == base {
+		t.Errorf("HubConfigHash() did not change with the spec")
+	}
+	if h, _ := HubConfigHash(m, map[string]string{"registration": "quay.io/open-cluster-management/registration:2.2.1"}); h == base {
+		t.Errorf("HubConfigHash() did not change with the image overrides")
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
+)
+
+// InUpdateWindow returns true if component image updates may roll out at the given time. Updates are always
+// allowed when no update window is set or any update window is invalid.
+func InUpdateWindow(m *operatorsv1.MultiClusterHub, now time.Time) bool {
+	if m.Spec.Overrides == nil || len(m.Spec.Overrides.UpdateWindow) == 0 || ValidateUpdateWindows(m) != nil {
+		return true
+	}
+
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	for _, w := range m.Spec.Overrides.UpdateWindow {
+		start, end, _ := parseWindow(w)
+		// A window opened yesterday may still be open if it crosses midnight
+		for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+			if !windowOpensOn(w, day.Weekday()) {
+				continue
+			}
+			opens, closes := day.Add(start), day.Add(end)
+			if end <= start {
+				closes = closes.AddDate(0, 0, 1)
+			}
+			if !now.Before(opens) && now.Before(closes) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NextUpdateWindow returns the next time an update window opens after the given time, or the
+// zero time if no window is set or any window is invalid
+func NextUpdateWindow(m *operatorsv1.MultiClusterHub, now time.Time) time.Time {
+	var next time.Time
+	if m.Spec.Overrides == nil || ValidateUpdateWindows(m) != nil {
+		return next
+	}
+
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	for _, w := range m.Spec.Overrides.UpdateWindow {
+		start, _, _ := parseWindow(w)
+		for d := 0; d <= 7; d++ {
+			day := today.AddDate(0, 0, d)
+			if !windowOpensOn(w, day.Weekday()) {
+				continue
+			}
+			opens := day.Add(start)
+			if opens.After(now) {
+				if next.IsZero() || opens.Before(next) {
+					next = opens
+				}
+				break
+			}
+		}
+	}
+	return next
+}
+
+// ValidateUpdateWindows returns an error describing the first invalid update window in CR overrides, or nil
+// if all are valid
+func ValidateUpdateWindows(m *operatorsv1.MultiClusterHub) error {
+	if m.Spec.Overrides == nil {
+		return nil
+	}
+	for i, w := range m.Spec.Overrides.UpdateWindow {
+		if _, _, err := parseWindow(w); err != nil {
+			return fmt.Errorf("update window %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// parseWindow returns the start and end of the window as offsets from midnight
+func parseWindow(w operatorsv1.TimeWindow) (time.Duration, time.Duration, error) {
+	start, err := time.Parse("15:04", w.Start)
+	if err != nil {
+		return 0, 0, fmt.Errorf("start %q is not in HH:MM format", w.Start)
+	}
+	end, err := time.Parse("15:04", w.End)
+	if err != nil {
+		return 0, 0, fmt.Errorf("end %q is not in HH:MM format", w.End)
+	}
+	for _, d := range w.Days {
+		if !isWeekday(d) {
+			return 0, 0, fmt.Errorf("day %q is not a day of the week", d)
+		}
+	}
+	midnight := time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)
+	return start.Sub(midnight), end.Sub(midnight), nil
+}
+
+// isWeekday returns true if the day names a day of the week by at least its first three letters
+func isWeekday(d string) bool {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if len(d) >= 3 && strings.HasPrefix(strings.ToLower(day.String()), strings.ToLower(d)) {
+			return true
+		}
+	}
+	return false
+}
+
+// windowOpensOn returns true if the window opens on the given weekday
+func windowOpensOn(w operatorsv1.TimeWindow, day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if len(d) >= 3 && strings.EqualFold(d[:3], day.String()[:3]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2021 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
+)
+
+func windowMCH(windows ...operatorsv1.TimeWindow) *operatorsv1.MultiClusterHub {
+	return &operatorsv1.MultiClusterHub{
+		Spec: operatorsv1.MultiClusterHubSpec{
+			Overrides: &operatorsv1.Overrides{UpdateWindow: windows},
+		},
+	}
+}
+
+func TestInUpdateWindow(t *testing.T) {
+	// 2021-03-03 is a Wednesday
+	wednesday := func(hour, min int) time.Time { return time.Date(2021, 3, 3, hour, min, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name string
+		mch  *operatorsv1.MultiClusterHub
+		now  time.Time
+		want bool
+	}{
+		{
+			name: "No window set",
+			mch:  &operatorsv1.MultiClusterHub{},
+			now:  wednesday(12, 0),
+			want: true,
+		},
+		{
+			name: "Inside daily window",
+			mch:  windowMCH(operatorsv1.TimeWindow{Start: "01:00", End: "05:00"}),
+			now:  wednesday(2, 30),
+			want: true,
+		},
+		{
+			name: "Outside daily window",
+			mch:  windowMCH(operatorsv1.TimeWindow{Start: "01:00", End: "05:00"}),
+			now:  wednesday(5, 0),
+			want: false,
+		},
+		{
+			name: "Window crossing midnight",
+			mch:  windowMCH(operatorsv1.TimeWindow{Days: []string{"Tue"}, Start: "22:00", End: "02:00"}),
+			now:  wednesday(1, 0),
+			want: true,
+		},
+		{
+			name: "Window on another day",
+			mch:  windowMCH(operatorsv1.TimeWindow{Days: []string{"Saturday", "sun"}, Start: "00:00", End: "23:59"}),
+			now:  wednesday(12, 0),
+			want: false,
+		},
+		{
+			name: "Invalid window does not defer updates",
+			mch:  windowMCH(operatorsv1.TimeWindow{Start: "01:00", End: "05:00"}, operatorsv1.TimeWindow{Start: "1am", End: "5am"}),
+			now:  wednesday(12, 0),
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InUpdateWindow(tt.mch, tt.now); got != tt.want {
+				t.Errorf("InUpdateWindow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextUpdateWindow(t *testing.T) {
+	now := time.Date(2021, 3, 3, 12, 0, 0, 0, time.UTC)
+
+	t.Run("No window set", func(t *testing.T) {
+		if got := NextUpdateWindow(&operatorsv1.MultiClusterHub{}, now); !got.IsZero() {
+			t.Errorf("NextUpdateWindow() = %v, want zero time", got)
+		}
+	})
+	t.Run("Next day", func(t *testing.T) {
+		mch := windowMCH(operatorsv1.TimeWindow{Start: "01:00", End: "05:00"})
+		want := time.Date(2021, 3, 4, 1, 0, 0, 0, time.UTC)
+		if got := NextUpdateWindow(mch, now); !got.Equal(want) {
+			t.Errorf("NextUpdateWindow() = %v, want %v", got, want)
+		}
+	})
+	t.Run("Earliest of several windows", func(t *testing.T) {
+		mch := windowMCH(
+			operatorsv1.TimeWindow{Days: []string{"Sat"}, Start: "01:00", End: "05:00"},
+			operatorsv1.TimeWindow{Days: []string{"Fri"}, Start: "20:00", End: "23:00"},
+		)
+		want := time.Date(2021, 3, 5, 20, 0, 0, 0, time.UTC)
+		if got := NextUpdateWindow(mch, now); !got.Equal(want) {
+			t.Errorf("NextUpdateWindow() = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestValidateUpdateWindows(t *testing.T) {
+	tests := []struct {
+		name    string
+		mch     *operatorsv1.MultiClusterHub
+		wantErr bool
+	}{
+		{name: "No window set", mch: &operatorsv1.MultiClusterHub{}, wantErr: false},
+		{name: "Valid windows", mch: windowMCH(operatorsv1.TimeWindow{Days: []string{"Saturday", "sun"}, Start: "22:00", End: "04:00"}), wantErr: false},
+		{name: "Bad start", mch: windowMCH(operatorsv1.TimeWindow{Start: "1am", End: "05:00"}), wantErr: true},
+		{name: "Bad end", mch: windowMCH(operatorsv1.TimeWindow{Start: "01:00", End: "25:00"}), wantErr: true},
+		{name: "Bad day", mch: windowMCH(operatorsv1.TimeWindow{Days: []string{"Weekend"}, Start: "01:00", End: "05:00"}), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateUpdateWindows(tt.mch); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateUpdateWindows() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("No next window for an invalid spec", func(t *testing.T) {
+		mch := windowMCH(operatorsv1.TimeWindow{Start: "1am", End: "5am"})
+		if got := NextUpdateWindow(mch, time.Now()); !got.IsZero() {
+			t.Errorf("NextUpdateWindow() = %v, want zero time", got)
+		}
+	})
+}
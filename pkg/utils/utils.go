@@ -7,6 +7,8 @@ package utils
 import (
 	"encoding/json"
 	"os"
+	"reflect"
+	"regexp"
 	"strings"
 	"time"
 
@@ -39,14 +41,27 @@ const (
 	// UnitTestEnvVar ...
 	UnitTestEnvVar = "UNIT_TEST"
 
+	// RequireOperatorNamespaceEnvVar names the environment variable that, when "true", requires hubs to be
+	// created in the namespace the operator runs in
+	RequireOperatorNamespaceEnvVar = "REQUIRE_OPERATOR_NAMESPACE"
+
 	// MCHOperatorName is the name of this operator deployment
 	MCHOperatorName = "multiclusterhub-operator"
 
 	// SubscriptionOperatorName is the name of the operator deployment managing application subscriptions
 	SubscriptionOperatorName = "multicluster-operators-standalone-subscription"
+
+	// DefaultPodSecurityLevel is the Pod Security admission level the hub components satisfy
+	DefaultPodSecurityLevel = "baseline"
+
+	// DefaultClustersPerReplica is the number of managed clusters each replica serves when scaling by cluster count
+	DefaultClustersPerReplica = 100
 )
 
 var (
+	// imageTagSuffixPattern matches characters allowed in an image tag, leaving room for the tag it is appended to
+	imageTagSuffixPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,127}$`)
+
 	// DefaultSSLCiphers defines the default cipher configuration used by management ingress
 	DefaultSSLCiphers = []string{
 		"ECDHE-ECDSA-AES256-GCM-SHA384",
@@ -126,6 +141,36 @@ func DefaultReplicaCount(mch *operatorsv1.MultiClusterHub) int {
 	return 2
 }
 
+// ClusterScalingEnabled returns true if component replicas scale with the number of managed clusters
+func ClusterScalingEnabled(m *operatorsv1.MultiClusterHub) bool {
+	return m.Spec.Overrides != nil && m.Spec.Overrides.ClusterScaling != nil
+}
+
+// ClusterScaledReplicas returns the replicas needed to serve the managed clusters, one per ClustersPerReplica
+// clusters, bounded by the configured minimum and maximum
+func ClusterScaledReplicas(m *operatorsv1.MultiClusterHub, clusters int) int32 {
+	cs := m.Spec.Overrides.ClusterScaling
+	perReplica, minReplicas, maxReplicas := cs.ClustersPerReplica, cs.MinReplicas, cs.MaxReplicas
+	if perReplica <= 0 {
+		perReplica = DefaultClustersPerReplica
+	}
+	if minReplicas <= 0 {
+		minReplicas = int32(DefaultReplicaCount(m))
+	}
+	if maxReplicas < minReplicas {
+		maxReplicas = minReplicas
+	}
+
+	replicas := (int32(clusters) + perReplica - 1) / perReplica
+	if replicas < minReplicas {
+		return minReplicas
+	}
+	if replicas > maxReplicas {
+		return maxReplicas
+	}
+	return replicas
+}
+
 //AvailabilityConfigIsValid ...
 func AvailabilityConfigIsValid(config operatorsv1.AvailabilityType) bool {
 	switch config {
@@ -185,6 +230,205 @@ func GetImagePullPolicy(m *operatorsv1.MultiClusterHub) v1.PullPolicy {
 	return m.Spec.Overrides.ImagePullPolicy
 }
 
+// GetTolerations returns the tolerations from the CR spec, or an empty list if none are set
+func GetTolerations(m *operatorsv1.MultiClusterHub) []corev1.Toleration {
+	return append([]corev1.Toleration{}, m.Spec.Tolerations...)
+}
+
+// GetPodSecurityLevel returns either the Pod Security admission level from CR overrides or default of baseline
+func GetPodSecurityLevel(m *operatorsv1.MultiClusterHub) string {
+	if m.Spec.Overrides == nil || m.Spec.Overrides.PodSecurityLevel == "" {
+		return DefaultPodSecurityLevel
+	}
+	return m.Spec.Overrides.PodSecurityLevel
+}
+
+// GetImageTagSuffix returns the image tag suffix from CR overrides, or an empty string if not set
+func GetImageTagSuffix(m *operatorsv1.MultiClusterHub) string {
+	if m.Spec.Overrides == nil {
+		return ""
+	}
+	return m.Spec.Overrides.ImageTagSuffix
+}
+
+// AppendImageTagSuffix appends the suffix after a dash to the tag of each tagged image. Digest-pinned and
+// untagged images are left unchanged.
+func AppendImageTagSuffix(imageOverrides map[string]string, suffix string) map[string]string {
+	for imageKey, imageRef := range imageOverrides {
+		if strings.Contains(imageRef, "@") || strings.LastIndex(imageRef, ":") <= strings.LastIndex(imageRef, "/") {
+			continue
+		}
+		imageOverrides[imageKey] = imageRef + "-" + suffix
+	}
+	return imageOverrides
+}
+
+// GetWebhookTLSSecret returns the externally provided webhook TLS secret from CR overrides, or an empty string
+// if the operator generates the webhook certificate
+func GetWebhookTLSSecret(m *operatorsv1.MultiClusterHub) string {
+	if m.Spec.Overrides == nil {
+		return ""
+	}
+	return m.Spec.Overrides.WebhookTLSSecret
+}
+
+// IsValidImageTagSuffix returns true if the suffix is a legal image tag fragment
+func IsValidImageTagSuffix(suffix string) bool {
+	return imageTagSuffixPattern.MatchString(suffix)
+}
+
+// GetRequiredOperators returns the OLM operators from CR overrides the hub waits for, or nil if not set
+func GetRequiredOperators(m *operatorsv1.MultiClusterHub) []operatorsv1.OperatorReference {
+	if m.Spec.Overrides == nil {
+		return nil
+	}
+	return m.Spec.Overrides.RequiredOperators
+}
+
+// GetPodSecurityContext returns the pod security context for components, with the seccomp profile from CR
+// overrides or default of RuntimeDefault
+func GetPodSecurityContext(m *operatorsv1.MultiClusterHub) *corev1.PodSecurityContext {
+	profile := &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	if m.Spec.Overrides != nil && m.Spec.Overrides.SeccompProfile != nil {
+		profile = m.Spec.Overrides.SeccompProfile.DeepCopy()
+	}
+	return &corev1.PodSecurityContext{SeccompProfile: profile}
+}
+
+// VerifyImageDigests returns true if the CR overrides request checking running pods for diverging image digests
+func VerifyImageDigests(m *operatorsv1.MultiClusterHub) bool {
+	return m.Spec.Overrides != nil && m.Spec.Overrides.VerifyImageDigests
+}
+
+// GetServiceType returns either the service type from CR overrides or default of ClusterIP
+func GetServiceType(m *operatorsv1.MultiClusterHub) corev1.ServiceType {
+	if m.Spec.Overrides == nil || m.Spec.Overrides.ServiceType == "" {
+		return corev1.ServiceTypeClusterIP
+	}
+	return m.Spec.Overrides.ServiceType
+}
+
+// GetNodePort returns the node port requested for the named service in CR overrides, or 0 to let
+// the cluster assign one. Node ports only apply to NodePort services.
+func GetNodePort(m *operatorsv1.MultiClusterHub, service string) int32 {
+	if GetServiceType(m) != corev1.ServiceTypeNodePort || m.Spec.Overrides.NodePorts == nil {
+		return 0
+	}
+	return m.Spec.Overrides.NodePorts[service]
+}
+
+// ValidateService returns the found service updated with the desired ports, selector, type and labels, and
+// whether an update is needed. Server-assigned fields such as the cluster IP are kept, as are assigned node
+// ports when the desired port at the same position and name leaves them unset.
+func ValidateService(found, desired *corev1.Service) (*corev1.Service, bool) {
+	ports := make([]corev1.ServicePort, len(desired.Spec.Ports))
+	for i, p := range desired.Spec.Ports {
+		if p.Protocol == "" {
+			p.Protocol = corev1.ProtocolTCP
+		}
+		if p.NodePort == 0 && desired.Spec.Type != corev1.ServiceTypeClusterIP &&
+			i < len(found.Spec.Ports) && found.Spec.Ports[i].Name == p.Name {
+			p.NodePort = found.Spec.Ports[i].NodePort
+		}
+		ports[i] = p
+	}
+
+	current := make([]corev1.ServicePort, len(found.Spec.Ports))
+	for i, p := range found.Spec.Ports {
+		if p.Protocol == "" {
+			p.Protocol = corev1.ProtocolTCP
+		}
+		current[i] = p
+	}
+
+	if reflect.DeepEqual(current, ports) &&
+		reflect.DeepEqual(found.Spec.Selector, desired.Spec.Selector) &&
+		found.Spec.Type == desired.Spec.Type &&
+		ContainsMap(found.Labels, desired.Labels) {
+		return found, false
+	}
+
+	updated := found.DeepCopy()
+	updated.Spec.Ports = ports
+	updated.Spec.Selector = desired.Spec.Selector
+	updated.Spec.Type = desired.Spec.Type
+	if updated.Labels == nil {
+		updated.Labels = map[string]string{}
+	}
+	for k, v := range desired.Labels {
+		updated.Labels[k] = v
+	}
+	return updated, true
+}
+
+// ValidatePersistentVolumeClaim returns the found claim updated with the desired storage request, and whether an
+// update is needed. Claims can only be expanded, so a smaller desired request is ignored, as are the storage class
+// and access modes which cannot change once the claim is created.
+func ValidatePersistentVolumeClaim(found, desired *corev1.PersistentVolumeClaim) (*corev1.PersistentVolumeClaim, bool) {
+	want := desired.Spec.Resources.Requests[corev1.ResourceStorage]
+	current := found.Spec.Resources.Requests[corev1.ResourceStorage]
+	if want.Cmp(current) <= 0 {
+		return found, false
+	}
+
+	updated := found.DeepCopy()
+	if updated.Spec.Resources.Requests == nil {
+		updated.Spec.Resources.Requests = corev1.ResourceList{}
+	}
+	updated.Spec.Resources.Requests[corev1.ResourceStorage] = want
+	return updated, true
+}
+
+// GetInstallTimeout returns the install timeout from CR overrides, or 0 if installs never time out
+func GetInstallTimeout(m *operatorsv1.MultiClusterHub) time.Duration {
+	if m.Spec.Overrides == nil || m.Spec.Overrides.InstallTimeout == nil {
+		return 0
+	}
+	return m.Spec.Overrides.InstallTimeout.Duration
+}
+
+// GetReadyCallbackURL returns the ready callback URL from CR overrides, or "" if no callback is configured
+func GetReadyCallbackURL(m *operatorsv1.MultiClusterHub) string {
+	if m.Spec.Overrides == nil {
+		return ""
+	}
+	return m.Spec.Overrides.ReadyCallbackURL
+}
+
+// GetReconcileErrorBudget returns the consecutive reconcile errors tolerated from CR overrides, or 0 if reconciliation never backs off
+func GetReconcileErrorBudget(m *operatorsv1.MultiClusterHub) int {
+	if m.Spec.Overrides == nil {
+		return 0
+	}
+	return m.Spec.Overrides.ReconcileErrorBudget
+}
+
+// GetEnvFrom returns the environment variable sources from CR overrides for the named component
+func GetEnvFrom(m *operatorsv1.MultiClusterHub, component string) []corev1.EnvFromSource {
+	if m.Spec.Overrides == nil || len(m.Spec.Overrides.EnvFrom[component]) == 0 {
+		return nil
+	}
+	return m.Spec.Overrides.EnvFrom[component]
+}
+
+// VerifyImageArchitecture returns true if component images should be checked against node architectures
+func VerifyImageArchitecture(m *operatorsv1.MultiClusterHub) bool {
+	return m.Spec.Overrides != nil && m.Spec.Overrides.VerifyImageArchitecture
+}
+
+// MergeContainerResources returns true if resources added to component containers should be kept during drift correction
+func MergeContainerResources(m *operatorsv1.MultiClusterHub) bool {
+	return m.Spec.Overrides != nil && m.Spec.Overrides.MergeContainerResources
+}
+
+// GetPersistence returns the persistent volume claim configuration from CR overrides, or nil if no claim is managed
+func GetPersistence(m *operatorsv1.MultiClusterHub) *operatorsv1.PersistenceConfig {
+	if m.Spec.Overrides == nil {
+		return nil
+	}
+	return m.Spec.Overrides.Persistence
+}
+
 // GetContainerArgs return arguments forfirst container in deployment
 func GetContainerArgs(dep *appsv1.Deployment) []string {
 	return dep.Spec.Template.Spec.Containers[0].Args
@@ -195,6 +439,11 @@ func GetContainerEnvVars(dep *appsv1.Deployment) []v1.EnvVar {
 	return dep.Spec.Template.Spec.Containers[0].Env
 }
 
+// GetContainerEnvFrom returns environment variable sources for first container in deployment
+func GetContainerEnvFrom(dep *appsv1.Deployment) []corev1.EnvFromSource {
+	return dep.Spec.Template.Spec.Containers[0].EnvFrom
+}
+
 // GetContainerVolumeMounts returns volume mount for first container in deployment
 func GetContainerVolumeMounts(dep *appsv1.Deployment) []corev1.VolumeMount {
 	return dep.Spec.Template.Spec.Containers[0].VolumeMounts
@@ -205,6 +454,28 @@ func GetContainerRequestResources(dep *appsv1.Deployment) corev1.ResourceList {
 	return dep.Spec.Template.Spec.Containers[0].Resources.Requests
 }
 
+// MergeResources returns the found resource requirements with every request and limit set in desired applied,
+// keeping those for other resources. Returns true if the found requirements were changed.
+func MergeResources(found, desired corev1.ResourceRequirements) (corev1.ResourceRequirements, bool) {
+	merged := *found.DeepCopy()
+	changed := false
+	merge := func(list *corev1.ResourceList, want corev1.ResourceList) {
+		for name, q := range want {
+			if current, ok := (*list)[name]; ok && current.Cmp(q) == 0 {
+				continue
+			}
+			if *list == nil {
+				*list = corev1.ResourceList{}
+			}
+			(*list)[name] = q.DeepCopy()
+			changed = true
+		}
+	}
+	merge(&merged.Requests, desired.Requests)
+	merge(&merged.Limits, desired.Limits)
+	return merged, changed
+}
+
 func IsUnitTest() bool {
 	if unitTest, found := os.LookupEnv(UnitTestEnvVar); found {
 		if unitTest == "true" {
@@ -214,6 +485,16 @@ func IsUnitTest() bool {
 	return false
 }
 
+// RequireOperatorNamespace returns true if hubs must be created in the namespace the operator runs in
+func RequireOperatorNamespace() bool {
+	return os.Getenv(RequireOperatorNamespaceEnvVar) == "true"
+}
+
+// GetOperatorNamespace returns the namespace the operator runs in, read from the POD_NAMESPACE environment variable
+func GetOperatorNamespace() (string, error) {
+	return findNamespace()
+}
+
 // FormatSSLCiphers converts an array of ciphers into a string consumed by the management
 // ingress chart
 func FormatSSLCiphers(ciphers []string) string {
@@ -6,12 +6,15 @@ package utils
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	operatorsv1 "github.com/open-cluster-management/multicloudhub-operator/pkg/apis/operator/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func TestContainsPullSecret(t *testing.T) {
@@ -195,6 +198,43 @@ func TestGetImagePullPolicy(t *testing.T) {
 	})
 }
 
+func TestGetNodePort(t *testing.T) {
+	clusterIPMCH := &operatorsv1.MultiClusterHub{
+		Spec: operatorsv1.MultiClusterHubSpec{
+			Overrides: &operatorsv1.Overrides{NodePorts: map[string]int32{"svc": 30100}},
+		},
+	}
+	nodePortMCH := &operatorsv1.MultiClusterHub{
+		Spec: operatorsv1.MultiClusterHubSpec{
+			Overrides: &operatorsv1.Overrides{
+				ServiceType: v1.ServiceTypeNodePort,
+				NodePorts:   map[string]int32{"svc": 30100},
+			},
+		},
+	}
+
+	t.Run("Default service type", func(t *testing.T) {
+		if got := GetServiceType(&operatorsv1.MultiClusterHub{}); got != v1.ServiceTypeClusterIP {
+			t.Errorf("GetServiceType() = %v, want %v", got, v1.ServiceTypeClusterIP)
+		}
+	})
+	t.Run("Node port ignored for ClusterIP services", func(t *testing.T) {
+		if got := GetNodePort(clusterIPMCH, "svc"); got != 0 {
+			t.Errorf("GetNodePort() = %v, want %v", got, 0)
+		}
+	})
+	t.Run("Node port set", func(t *testing.T) {
+		if got := GetNodePort(nodePortMCH, "svc"); got != 30100 {
+			t.Errorf("GetNodePort() = %v, want %v", got, 30100)
+		}
+	})
+	t.Run("Node port not listed", func(t *testing.T) {
+		if got := GetNodePort(nodePortMCH, "other"); got != 0 {
+			t.Errorf("GetNodePort() = %v, want %v", got, 0)
+		}
+	})
+}
+
 func TestDefaultReplicaCount(t *testing.T) {
 	mchDefault := &operatorsv1.MultiClusterHub{}
 	mchNonHA := &operatorsv1.MultiClusterHub{
@@ -280,3 +320,270 @@ func TestTrackedNamespaces(t *testing.T) {
 		})
 	}
 }
+
+func TestAppendImageTagSuffix(t *testing.T) {
+	imageOverrides := map[string]string{
+		"tagged":   "quay.io/open-cluster-management/multiclusterhub-repo:2.2.0",
+		"port":     "registry.example.com:5000/open-cluster-management/ocm-webhook:2.2.0",
+		"digest":   "quay.io/open-cluster-management/registration@sha256:abc123",
+		"untagged": "registry.example.com:5000/open-cluster-management/placement",
+	}
+	want := map[string]string{
+		"tagged":   "quay.io/open-cluster-management/multiclusterhub-repo:2.2.0-debug",
+		"port":     "registry.example.com:5000/open-cluster-management/ocm-webhook:2.2.0-debug",
+		"digest":   "quay.io/open-cluster-management/registration@sha256:abc123",
+		"untagged": "registry.example.com:5000/open-cluster-management/placement",
+	}
+	if got := AppendImageTagSuffix(imageOverrides, "debug"); !reflect.DeepEqual(got, want) {
+		t.Errorf("AppendImageTagSuffix() = %v, want %v", got, want)
+	}
+}
+
+func TestIsValidImageTagSuffix(t *testing.T) {
+	tests := []struct {
+		suffix string
+		want   bool
+	}{
+		{"-debug", true},
+		{"_rc.1", true},
+		{"", false},
+		{"-debug build", false},
+		{":debug", false},
+		{strings.Repeat("a", 128), false},
+	}
+	for _, tt := range tests {
+		if got := IsValidImageTagSuffix(tt.suffix); got != tt.want {
+			t.Errorf("IsValidImageTagSuffix(%q) = %v, want %v", tt.suffix, got, tt.want)
+		}
+	}
+}
+
+func TestGetPodSecurityContext(t *testing.T) {
+	t.Run("Default seccomp profile", func(t *testing.T) {
+		got := GetPodSecurityContext(&operatorsv1.MultiClusterHub{}).SeccompProfile
+		if got == nil || got.Type != v1.SeccompProfileTypeRuntimeDefault {
+			t.Errorf("GetPodSecurityContext() seccompProfile = %v, want %v", got, v1.SeccompProfileTypeRuntimeDefault)
+		}
+	})
+	t.Run("Localhost seccomp profile", func(t *testing.T) {
+		localhost := "profiles/hub.json"
+		want := &v1.SeccompProfile{Type: v1.SeccompProfileTypeLocalhost, LocalhostProfile: &localhost}
+		mch := &operatorsv1.MultiClusterHub{
+			Spec: operatorsv1.MultiClusterHubSpec{Overrides: &operatorsv1.Overrides{SeccompProfile: want}},
+		}
+		if got := GetPodSecurityContext(mch).SeccompProfile; !reflect.DeepEqual(got, want) {
+			t.Errorf("GetPodSecurityContext() seccompProfile = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestValidateService(t *testing.T) {
+	desired := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "repo"}},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "repo"},
+			Ports:    []corev1.ServicePort{{Port: 3000, TargetPort: intstr.FromInt(3000)}},
+			Type:     corev1.ServiceTypeNodePort,
+		},
+	}
+	found := desired.DeepCopy()
+	found.Labels["extra"] = "kept"
+	found.Spec.ClusterIP = "172.30.0.10"
+	found.Spec.Ports[0].Protocol = corev1.ProtocolTCP
+	found.Spec.Ports[0].NodePort = 30000
+
+	tests := []struct {
+		name  string
+		drift func(s *corev1.Service)
+		want  bool
+	}{
+		{
+			name:  "Server-assigned fields only",
+			drift: func(s *corev1.Service) {},
+			want:  false,
+		},
+		{
+			name:  "Port changed",
+			drift: func(s *corev1.Service) { s.Spec.Ports[0].Port = 8080 },
+			want:  true,
+		},
+		{
+			name:  "Selector changed",
+			drift: func(s *corev1.Service) { s.Spec.Selector = map[string]string{"app": "other"} },
+			want:  true,
+		},
+		{
+			name:  "Type changed",
+			drift: func(s *corev1.Service) { s.Spec.Type = corev1.ServiceTypeClusterIP },
+			want:  true,
+		},
+		{
+			name:  "Label removed",
+			drift: func(s *corev1.Service) { delete(s.Labels, "app") },
+			want:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := found.DeepCopy()
+			tt.drift(svc)
+			got, needsUpdate := ValidateService(svc, desired)
+			if needsUpdate != tt.want {
+				t.Fatalf("ValidateService() needsUpdate = %v, want %v", needsUpdate, tt.want)
+			}
+			if got.Spec.ClusterIP != "172.30.0.10" || got.Labels["extra"] != "kept" {
+				t.Errorf("ValidateService() did not preserve the cluster IP and existing labels")
+			}
+			if got.Spec.Ports[0].NodePort != 30000 {
+				t.Errorf("ValidateService() nodePort = %d, want %d", got.Spec.Ports[0].NodePort, 30000)
+			}
+			if _, again := ValidateService(got, desired); again {
+				t.Errorf("ValidateService() still needs an update after applying its result")
+			}
+		})
+	}
+}
+
+func TestClusterScaledReplicas(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   operatorsv1.AvailabilityType
+		scaling  operatorsv1.ClusterScaling
+		clusters int
+		want     int32
+	}{
+		{
+			name:     "Default minimum",
+			config:   operatorsv1.HAHigh,
+			scaling:  operatorsv1.ClusterScaling{MaxReplicas: 5},
+			clusters: 10,
+			want:     2,
+		},
+		{
+			name:     "Default clusters per replica",
+			config:   operatorsv1.HABasic,
+			scaling:  operatorsv1.ClusterScaling{MaxReplicas: 5},
+			clusters: 250,
+			want:     3,
+		},
+		{
+			name:     "Proportional",
+			config:   operatorsv1.HABasic,
+			scaling:  operatorsv1.ClusterScaling{ClustersPerReplica: 10, MinReplicas: 1, MaxReplicas: 5},
+			clusters: 31,
+			want:     4,
+		},
+		{
+			name:     "Capped at maximum",
+			config:   operatorsv1.HABasic,
+			scaling:  operatorsv1.ClusterScaling{ClustersPerReplica: 10, MinReplicas: 1, MaxReplicas: 5},
+			clusters: 1000,
+			want:     5,
+		},
+		{
+			name:     "Maximum below minimum",
+			config:   operatorsv1.HABasic,
+			scaling:  operatorsv1.ClusterScaling{ClustersPerReplica: 10, MinReplicas: 3, MaxReplicas: 2},
+			clusters: 1000,
+			want:     3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scaling := tt.scaling
+			mch := &operatorsv1.MultiClusterHub{Spec: operatorsv1.MultiClusterHubSpec{
+				AvailabilityConfig: tt.config,
+				Overrides:          &operatorsv1.Overrides{ClusterScaling: &scaling},
+			}}
+			if got := ClusterScaledReplicas(mch, tt.clusters); got != tt.want {
+				t.Errorf("ClusterScaledReplicas() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeResources(t *testing.T) {
+	desired := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+		Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+
+	tests := []struct {
+		name        string
+		found       corev1.ResourceRequirements
+		want        corev1.ResourceRequirements
+		wantChanged bool
+	}{
+		{
+			name:        "Matching resources",
+			found:       *desired.DeepCopy(),
+			want:        desired,
+			wantChanged: false,
+		},
+		{
+			name:        "Missing resources",
+			found:       corev1.ResourceRequirements{},
+			want:        desired,
+			wantChanged: true,
+		},
+		{
+			name: "Added resources kept and operator-set values enforced",
+			found: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi"), corev1.ResourceCPU: resource.MustParse("100m")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi"), corev1.ResourceCPU: resource.MustParse("1")},
+			},
+			want: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi"), corev1.ResourceCPU: resource.MustParse("100m")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi"), corev1.ResourceCPU: resource.MustParse("1")},
+			},
+			wantChanged: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := MergeResources(tt.found, desired)
+			if changed != tt.wantChanged {
+				t.Errorf("MergeResources() changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergeResources() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePersistentVolumeClaim(t *testing.T) {
+	claim := func(size string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			Spec: corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		found   string
+		desired string
+		want    string
+		update  bool
+	}{
+		{name: "Same size", found: "10Gi", desired: "10Gi", want: "10Gi", update: false},
+		{name: "Expanded", found: "10Gi", desired: "20Gi", want: "20Gi", update: true},
+		{name: "Shrinking ignored", found: "20Gi", desired: "10Gi", want: "20Gi", update: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, update := ValidatePersistentVolumeClaim(claim(tt.found), claim(tt.desired))
+			if update != tt.update {
+				t.Errorf("ValidatePersistentVolumeClaim() update = %v, want %v", update, tt.update)
+			}
+			size := got.Spec.Resources.Requests[corev1.ResourceStorage]
+			if want := resource.MustParse(tt.want); size.Cmp(want) != 0 {
+				t.Errorf("ValidatePersistentVolumeClaim() size = %s, want %s", size.String(), tt.want)
+			}
+		})
+	}
+}